@@ -24,6 +24,10 @@ const (
 
 // API handler is the default handler which takes api.Request and returns api.Response
 func (a *apiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !handler.Authenticate(w, r, a.opts) {
+		return
+	}
+
 	bsize := handler.DefaultMaxRecvSize
 	if a.opts.MaxRecvSize > 0 {
 		bsize = a.opts.MaxRecvSize
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/apikey"
+	"c-z.dev/go-micro/errors"
+)
+
+// APIKeyHeader is the header api keys are passed in
+var APIKeyHeader = "X-API-Key"
+
+// Authenticate replaces the api key passed with the request by a bearer token issued for
+// the key, so the services called authorize the request with the key's scopes. If
+// the key is invalid an unauthorized error is written and false returned, any other
+// error is written as an internal server error.
+func Authenticate(w http.ResponseWriter, r *http.Request, opts Options) bool {
+	key := r.Header.Get(APIKeyHeader)
+	if len(key) == 0 || opts.APIKeys == nil {
+		return true
+	}
+	r.Header.Del(APIKeyHeader)
+
+	t, err := opts.APIKeys.Token(key)
+	if err != nil {
+		er := errors.Unauthorized(opts.Namespace, err.Error())
+		code := http.StatusUnauthorized
+		if err != apikey.ErrInvalidKey {
+			er = errors.InternalServerError(opts.Namespace, err.Error())
+			code = http.StatusInternalServerError
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write([]byte(er.Error()))
+		return false
+	}
+
+	r.Header.Set("Authorization", auth.BearerScheme+t.AccessToken)
+	return true
+}
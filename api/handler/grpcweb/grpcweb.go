@@ -0,0 +1,266 @@
+// Package grpcweb is an api handler which lets browsers call grpc services
+// with the gRPC-Web and Connect protocols. The services are called by their
+// grpc path, e.g. /go.micro.api.greeter.Greeter/Hello, and must be in the
+// namespace of the handler.
+package grpcweb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"c-z.dev/go-micro/api/handler"
+	"c-z.dev/go-micro/client"
+	"c-z.dev/go-micro/client/selector"
+	raw "c-z.dev/go-micro/codec/bytes"
+	"c-z.dev/go-micro/errors"
+	"c-z.dev/go-micro/logger"
+	"c-z.dev/go-micro/metadata"
+	"c-z.dev/go-micro/registry"
+	"c-z.dev/go-micro/util/ctx"
+)
+
+const (
+	Handler = "grpcweb"
+)
+
+type grpcwebHandler struct {
+	opts handler.Options
+}
+
+// strategy is a hack for selection
+func strategy(services []*registry.Service) selector.Strategy {
+	return func(_ []*registry.Service) selector.Next {
+		// ignore input to this function, use services above
+		return selector.Random(services)
+	}
+}
+
+// parsePath returns the service and endpoint of a grpc path /pkg.Foo/Bar
+func parsePath(path string) (string, string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return "", "", false
+	}
+	idx := strings.LastIndex(parts[0], ".")
+	if idx <= 0 || idx == len(parts[0])-1 {
+		return "", "", false
+	}
+	return parts[0][:idx], parts[0][idx+1:] + "." + parts[1], true
+}
+
+// isStream returns true if the endpoint streams its responses
+func isStream(services []*registry.Service, endpoint string) bool {
+	for _, service := range services {
+		for _, ep := range service.Endpoints {
+			if ep.Name == endpoint {
+				return ep.Metadata["stream"] == "true"
+			}
+		}
+	}
+	return false
+}
+
+func (h *grpcwebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !handler.Authenticate(w, r, h.opts) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.opts.MaxRecvSize)
+	defer r.Body.Close()
+
+	if r.Method != http.MethodPost {
+		h.writeStatus(w, http.StatusMethodNotAllowed, []byte(errors.MethodNotAllowed(h.opts.Namespace, "method %s not allowed", r.Method).Error()))
+		return
+	}
+
+	req, ok := parseContentType(r.Header.Get("Content-Type"))
+	if !ok {
+		h.writeStatus(w, http.StatusUnsupportedMediaType, []byte(errors.New(h.opts.Namespace, "unsupported content type "+r.Header.Get("Content-Type"), http.StatusUnsupportedMediaType).Error()))
+		return
+	}
+
+	// only the services in the namespace of the handler can be called
+	name, endpoint, ok := parsePath(r.URL.Path)
+	if !ok || !strings.HasPrefix(name, h.opts.Namespace+".") {
+		h.write(w, req, nil, microError(errors.NotFound(h.opts.Namespace, "unknown method %s", r.URL.Path)))
+		return
+	}
+
+	msgs, err := readBody(r.Body, req)
+	if err != nil {
+		h.write(w, req, nil, microError(errors.BadRequest(h.opts.Namespace, err.Error())))
+		return
+	}
+	// only unary and server streaming calls are supported
+	if len(msgs) > 1 {
+		h.write(w, req, nil, microError(errors.BadRequest(h.opts.Namespace, "client streaming is not supported")))
+		return
+	}
+	var body []byte
+	if len(msgs) == 1 {
+		body = msgs[0]
+	}
+
+	reg := registry.DefaultRegistry
+	if h.opts.Router != nil {
+		reg = h.opts.Router.Options().Registry
+	}
+	services, err := reg.GetService(name)
+	if err != nil {
+		h.write(w, req, nil, microError(errors.InternalServerError(h.opts.Namespace, err.Error())))
+		return
+	}
+
+	// create context
+	cx := ctx.FromRequest(r)
+	// get context from http handler wrappers
+	md, ok := metadata.FromContext(r.Context())
+	if !ok {
+		md = make(metadata.Metadata)
+	}
+	md["Host"] = r.Host
+	md["Method"] = r.Method
+	// get canonical headers
+	for k := range r.Header {
+		md[textproto.CanonicalMIMEHeaderKey(k)] = r.Header.Get(k)
+	}
+	cx = metadata.MergeContext(cx, md, true)
+
+	if d := timeout(r); d > 0 {
+		var cancel context.CancelFunc
+		cx, cancel = context.WithTimeout(cx, d)
+		defer cancel()
+	}
+
+	c := h.opts.Client
+	so := selector.WithStrategy(strategy(services))
+	request := c.NewRequest(name, endpoint, &raw.Frame{Data: body}, client.WithContentType(req.codec))
+
+	if !isStream(services, endpoint) {
+		rsp := &raw.Frame{}
+		if err := c.Call(cx, request, rsp, client.WithSelectOption(so)); err != nil {
+			h.write(w, req, nil, microError(err))
+			return
+		}
+		h.write(w, req, [][]byte{rsp.Data}, nil)
+		return
+	}
+
+	// a unary connect request has no way to return a stream
+	if req.protocol == protocolConnect {
+		h.write(w, req, nil, microError(errors.BadRequest(h.opts.Namespace, "%s is a streaming endpoint", endpoint)))
+		return
+	}
+
+	request = c.NewRequest(name, endpoint, &raw.Frame{Data: body}, client.WithContentType(req.codec), client.StreamingRequest())
+	stream, err := c.Stream(cx, request, client.WithSelectOption(so))
+	if err != nil {
+		h.write(w, req, nil, microError(err))
+		return
+	}
+	defer stream.Close()
+
+	if err := stream.Send(&raw.Frame{Data: body}); err != nil {
+		h.write(w, req, nil, microError(err))
+		return
+	}
+
+	h.writeHeader(w, req, http.StatusOK)
+	for {
+		rsp := &raw.Frame{}
+		if err := stream.Recv(rsp); err == io.EOF {
+			break
+		} else if err != nil {
+			h.writeEnd(w, req, microError(err))
+			return
+		}
+		h.writeMessage(w, req, rsp.Data)
+	}
+	h.writeEnd(w, req, nil)
+}
+
+func (h *grpcwebHandler) String() string {
+	return Handler
+}
+
+// write writes a complete response of the messages or the error
+func (h *grpcwebHandler) write(w http.ResponseWriter, req *request, msgs [][]byte, err *errors.Error) {
+	if req.protocol == protocolConnect {
+		if err != nil {
+			b, _ := json.Marshal(newConnectError(err))
+			h.writeStatus(w, int(httpCode(grpcCode(err))), b)
+			return
+		}
+		w.Header().Set("Content-Type", req.contentType)
+		w.WriteHeader(http.StatusOK)
+		for _, msg := range msgs {
+			h.writeBytes(w, msg)
+		}
+		return
+	}
+
+	h.writeHeader(w, req, http.StatusOK)
+	for _, msg := range msgs {
+		h.writeMessage(w, req, msg)
+	}
+	h.writeEnd(w, req, err)
+}
+
+// writeHeader writes the header of a grpc-web or connect streaming response
+func (h *grpcwebHandler) writeHeader(w http.ResponseWriter, req *request, code int) {
+	w.Header().Set("Content-Type", req.contentType)
+	w.WriteHeader(code)
+}
+
+// writeMessage writes a message envelope and flushes it to the client
+func (h *grpcwebHandler) writeMessage(w http.ResponseWriter, req *request, msg []byte) {
+	h.writeFrame(w, req, 0, msg)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writeEnd writes the trailers of a grpc-web response or the end of a connect stream
+func (h *grpcwebHandler) writeEnd(w http.ResponseWriter, req *request, err *errors.Error) {
+	if req.protocol == protocolConnectStream {
+		h.writeFrame(w, req, flagEndStream, endStream(err))
+		return
+	}
+	h.writeFrame(w, req, flagTrailer, trailers(err))
+}
+
+func (h *grpcwebHandler) writeFrame(w http.ResponseWriter, req *request, flags byte, msg []byte) {
+	b := envelope(flags, msg)
+	if req.protocol == protocolGRPCWebText {
+		b = []byte(base64.StdEncoding.EncodeToString(b))
+	}
+	h.writeBytes(w, b)
+}
+
+// writeStatus writes a json error with the status code
+func (h *grpcwebHandler) writeStatus(w http.ResponseWriter, code int, b []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	h.writeBytes(w, b)
+}
+
+func (h *grpcwebHandler) writeBytes(w http.ResponseWriter, b []byte) {
+	if _, err := w.Write(b); err != nil {
+		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+			logger.Error(err)
+		}
+	}
+}
+
+// NewHandler returns a handler translating gRPC-Web and Connect requests to
+// calls of the services named by the path of the request, /pkg.Foo/Bar
+func NewHandler(opts ...handler.Option) handler.Handler {
+	return &grpcwebHandler{
+		opts: handler.NewOptions(opts...),
+	}
+}
@@ -0,0 +1,275 @@
+package grpcweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"c-z.dev/go-micro/api/handler"
+	"c-z.dev/go-micro/api/router"
+	regRouter "c-z.dev/go-micro/api/router/registry"
+	"c-z.dev/go-micro/client"
+	"c-z.dev/go-micro/client/grpc"
+	raw "c-z.dev/go-micro/codec/bytes"
+	"c-z.dev/go-micro/errors"
+	"c-z.dev/go-micro/registry"
+	"c-z.dev/go-micro/registry/memory"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// testClient echoes the request, requests for "missing" fail
+type testClient struct {
+	client.Client
+}
+
+func (c *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	body := req.Body().(*raw.Frame).Data
+	if string(body) == "missing" {
+		return errors.NotFound("greeter", "not found")
+	}
+	rsp.(*raw.Frame).Data = append([]byte(req.Endpoint()+":"), body...)
+	return nil
+}
+
+func (c *testClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	return &testStream{ctx: ctx, req: req}, nil
+}
+
+// testStream sends the request back three times, requests for "missing"
+// fail after the first response like a grpc stream does
+type testStream struct {
+	ctx  context.Context
+	req  client.Request
+	body []byte
+	sent int
+}
+
+func (s *testStream) Context() context.Context   { return s.ctx }
+func (s *testStream) Request() client.Request    { return s.req }
+func (s *testStream) Response() client.Response  { return nil }
+func (s *testStream) Error() error               { return nil }
+func (s *testStream) Close() error               { return nil }
+func (s *testStream) Send(msg interface{}) error { s.body = msg.(*raw.Frame).Data; return nil }
+
+func (s *testStream) Recv(msg interface{}) error {
+	if s.sent == 1 && string(s.body) == "missing" {
+		st, _ := status.New(codes.NotFound, "not found").WithDetails(errors.NotFound("greeter", "not found").(*errors.Error))
+		return st.Err()
+	}
+	if s.sent == 3 {
+		return io.EOF
+	}
+	s.sent++
+	msg.(*raw.Frame).Data = s.body
+	return nil
+}
+
+func testHandler(t *testing.T) (handler.Handler, func()) {
+	reg := memory.NewRegistry()
+	if err := reg.Register(&registry.Service{
+		Name:    "go.micro.api.greeter",
+		Version: "latest",
+		Nodes:   []*registry.Node{{Id: "greeter-1", Address: "127.0.0.1:8080"}},
+		Endpoints: []*registry.Endpoint{
+			{Name: "Greeter.Hello"},
+			{Name: "Greeter.Stream", Metadata: map[string]string{"stream": "true"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// services outside the namespace can't be called
+	if err := reg.Register(&registry.Service{
+		Name:      "go.micro.auth",
+		Version:   "latest",
+		Nodes:     []*registry.Node{{Id: "auth-1", Address: "127.0.0.1:8081"}},
+		Endpoints: []*registry.Endpoint{{Name: "Auth.Inspect"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	rt := regRouter.NewRouter(router.WithRegistry(reg))
+
+	h := NewHandler(
+		handler.WithRouter(rt),
+		handler.WithClient(&testClient{grpc.NewClient()}),
+	)
+	return h, func() { rt.Close() }
+}
+
+type frame struct {
+	flags byte
+	data  []byte
+}
+
+func readFrames(t *testing.T, b []byte) []frame {
+	var frames []frame
+	for len(b) > 0 {
+		if len(b) < 5 {
+			t.Fatalf("Malformed frame %q", b)
+		}
+		n := binary.BigEndian.Uint32(b[1:5])
+		frames = append(frames, frame{b[0], b[5 : 5+n]})
+		b = b[5+n:]
+	}
+	return frames
+}
+
+func serve(h handler.Handler, path, ct string, body []byte) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("POST", path, bytes.NewReader(body))
+	r.Header.Set("Content-Type", ct)
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestGRPCWeb(t *testing.T) {
+	h, stop := testHandler(t)
+	defer stop()
+
+	w := serve(h, "/go.micro.api.greeter.Greeter/Hello", "application/grpc-web+proto", envelope(0, []byte("john")))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/grpc-web+proto" {
+		t.Fatalf("Expected application/grpc-web+proto got %s", ct)
+	}
+	frames := readFrames(t, w.Body.Bytes())
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames got %d", len(frames))
+	}
+	if string(frames[0].data) != "Greeter.Hello:john" {
+		t.Fatalf("Expected Greeter.Hello:john got %s", frames[0].data)
+	}
+	if frames[1].flags != flagTrailer || !strings.Contains(string(frames[1].data), "grpc-status: 0") {
+		t.Fatalf("Expected ok trailer got %q", frames[1].data)
+	}
+
+	// text mode encodes each frame
+	body := base64.StdEncoding.EncodeToString(envelope(0, []byte("jane")))
+	w = serve(h, "/go.micro.api.greeter.Greeter/Hello", "application/grpc-web-text", []byte(body))
+	b, err := decodeText(w.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames = readFrames(t, b)
+	if len(frames) != 2 || string(frames[0].data) != "Greeter.Hello:jane" {
+		t.Fatalf("Expected Greeter.Hello:jane got %v", frames)
+	}
+}
+
+func TestGRPCWebStream(t *testing.T) {
+	h, stop := testHandler(t)
+	defer stop()
+
+	w := serve(h, "/go.micro.api.greeter.Greeter/Stream", "application/grpc-web", envelope(0, []byte("john")))
+	frames := readFrames(t, w.Body.Bytes())
+	if len(frames) != 4 {
+		t.Fatalf("Expected 4 frames got %d", len(frames))
+	}
+	for _, f := range frames[:3] {
+		if string(f.data) != "john" {
+			t.Fatalf("Expected john got %s", f.data)
+		}
+	}
+
+	// errors are returned in the trailer with the error as a detail
+	w = serve(h, "/go.micro.api.greeter.Greeter/Stream", "application/grpc-web", envelope(0, []byte("missing")))
+	frames = readFrames(t, w.Body.Bytes())
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames got %d", len(frames))
+	}
+	tr := frames[1]
+	if tr.flags != flagTrailer || !strings.Contains(string(tr.data), "grpc-status: 5") {
+		t.Fatalf("Expected not found trailer got %q", tr.data)
+	}
+
+	var details string
+	for _, line := range strings.Split(string(tr.data), "\r\n") {
+		if strings.HasPrefix(line, "grpc-status-details-bin: ") {
+			details = strings.TrimPrefix(line, "grpc-status-details-bin: ")
+		}
+	}
+	pb, err := base64.RawStdEncoding.DecodeString(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := &spb.Status{}
+	if err := proto.Unmarshal(pb, st); err != nil {
+		t.Fatal(err)
+	}
+	verr := microError(status.FromProto(st).Err())
+	if verr.Id != "greeter" || verr.Code != http.StatusNotFound {
+		t.Fatalf("Expected greeter not found error got %v", verr)
+	}
+
+	// client streaming isn't supported
+	body := append(envelope(0, []byte("a")), envelope(0, []byte("b"))...)
+	w = serve(h, "/go.micro.api.greeter.Greeter/Stream", "application/grpc-web", body)
+	frames = readFrames(t, w.Body.Bytes())
+	if len(frames) != 1 || !strings.Contains(string(frames[0].data), "grpc-status: 3") {
+		t.Fatalf("Expected invalid argument trailer got %v", frames)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	h, stop := testHandler(t)
+	defer stop()
+
+	w := serve(h, "/go.micro.api.greeter.Greeter/Hello", "application/json", []byte("john"))
+	if w.Code != http.StatusOK || w.Body.String() != "Greeter.Hello:john" {
+		t.Fatalf("Expected 200 Greeter.Hello:john got %d %s", w.Code, w.Body.String())
+	}
+
+	w = serve(h, "/go.micro.api.greeter.Greeter/Hello", "application/proto", []byte("missing"))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404 got %d", w.Code)
+	}
+	var ce connectError
+	if err := json.Unmarshal(w.Body.Bytes(), &ce); err != nil {
+		t.Fatal(err)
+	}
+	if ce.Code != "not_found" || len(ce.Details) != 1 || ce.Details[0].Type != "errors.Error" {
+		t.Fatalf("Expected not_found with error detail got %+v", ce)
+	}
+
+	// streaming endpoints need the streaming protocol
+	w = serve(h, "/go.micro.api.greeter.Greeter/Stream", "application/json", []byte("john"))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 got %d", w.Code)
+	}
+
+	w = serve(h, "/go.micro.api.greeter.Greeter/Stream", "application/connect+json", envelope(0, []byte("john")))
+	frames := readFrames(t, w.Body.Bytes())
+	if len(frames) != 4 {
+		t.Fatalf("Expected 4 frames got %d", len(frames))
+	}
+	if end := frames[3]; end.flags != flagEndStream || string(end.data) != "{}" {
+		t.Fatalf("Expected empty end stream got %q", end.data)
+	}
+
+	w = serve(h, "/go.micro.api.greeter.Greeter/Stream", "application/connect+json", envelope(0, []byte("missing")))
+	frames = readFrames(t, w.Body.Bytes())
+	if end := frames[len(frames)-1]; end.flags != flagEndStream || !strings.Contains(string(end.data), `"code":"not_found"`) {
+		t.Fatalf("Expected not_found end stream got %q", end.data)
+	}
+
+	w = serve(h, "/go.micro.auth.Auth/Inspect", "application/json", []byte("john"))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404 for a service outside the namespace got %d %s", w.Code, w.Body.String())
+	}
+
+	w = serve(h, "/go.micro.api.greeter.Greeter/Hello", "text/plain", []byte("john"))
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("Expected 415 got %d", w.Code)
+	}
+}
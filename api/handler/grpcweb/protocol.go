@@ -0,0 +1,345 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"c-z.dev/go-micro/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// protocol a request is made with
+type protocol int
+
+const (
+	protocolGRPCWeb protocol = iota
+	protocolGRPCWebText
+	protocolConnect
+	protocolConnectStream
+)
+
+const (
+	// flags of a message envelope
+	flagCompressed = 0x01
+	flagEndStream  = 0x02
+	flagTrailer    = 0x80
+)
+
+// request is the protocol and codec parsed from the content type
+type request struct {
+	protocol protocol
+	// contentType of the response
+	contentType string
+	// codec the service is called with
+	codec string
+}
+
+// parseContentType returns the protocol of the content type, false if it isn't supported
+func parseContentType(ct string) (*request, bool) {
+	if idx := strings.IndexRune(ct, ';'); idx >= 0 {
+		ct = ct[:idx]
+	}
+	ct = strings.TrimSpace(strings.ToLower(ct))
+
+	codec := func(sub string) (string, bool) {
+		switch sub {
+		case "", "proto":
+			return "application/grpc+proto", true
+		case "json":
+			return "application/grpc+json", true
+		}
+		return "", false
+	}
+
+	var p protocol
+	var sub string
+	switch {
+	case ct == "application/grpc-web-text" || strings.HasPrefix(ct, "application/grpc-web-text+"):
+		p, sub = protocolGRPCWebText, strings.TrimPrefix(strings.TrimPrefix(ct, "application/grpc-web-text"), "+")
+	case ct == "application/grpc-web" || strings.HasPrefix(ct, "application/grpc-web+"):
+		p, sub = protocolGRPCWeb, strings.TrimPrefix(strings.TrimPrefix(ct, "application/grpc-web"), "+")
+	case strings.HasPrefix(ct, "application/connect+"):
+		p, sub = protocolConnectStream, strings.TrimPrefix(ct, "application/connect+")
+	case ct == "application/proto" || ct == "application/json":
+		p, sub = protocolConnect, strings.TrimPrefix(ct, "application/")
+	default:
+		return nil, false
+	}
+
+	c, ok := codec(sub)
+	if !ok {
+		return nil, false
+	}
+	return &request{protocol: p, contentType: ct, codec: c}, true
+}
+
+// envelope encodes a message with the flags and length prefix used by both protocols
+func envelope(flags byte, msg []byte) []byte {
+	b := make([]byte, 5+len(msg))
+	b[0] = flags
+	binary.BigEndian.PutUint32(b[1:5], uint32(len(msg)))
+	copy(b[5:], msg)
+	return b
+}
+
+// readEnvelopes decodes the messages of a request body
+func readEnvelopes(body []byte) ([][]byte, error) {
+	var msgs [][]byte
+	for len(body) > 0 {
+		if len(body) < 5 {
+			return nil, fmt.Errorf("malformed message envelope")
+		}
+		if body[0]&flagCompressed != 0 {
+			return nil, fmt.Errorf("compressed messages are not supported")
+		}
+		n := binary.BigEndian.Uint32(body[1:5])
+		if uint32(len(body)-5) < n {
+			return nil, fmt.Errorf("message envelope length %d exceeds body", n)
+		}
+		msgs = append(msgs, body[5:5+n])
+		body = body[5+n:]
+	}
+	return msgs, nil
+}
+
+// decodeText decodes a grpc-web-text body, which may be made of several padded base64 chunks
+func decodeText(body []byte) ([]byte, error) {
+	body = bytes.Join(bytes.Fields(body), nil)
+
+	var out []byte
+	for len(body) > 0 {
+		// a chunk ends after its padding
+		end := len(body)
+		if i := bytes.IndexByte(body, '='); i >= 0 {
+			end = i
+			for end < len(body) && body[end] == '=' {
+				end++
+			}
+		}
+		b, err := base64.StdEncoding.DecodeString(string(body[:end]))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b...)
+		body = body[end:]
+	}
+	return out, nil
+}
+
+// readBody reads the request messages
+func readBody(r io.Reader, req *request) ([][]byte, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.protocol {
+	case protocolConnect:
+		return [][]byte{body}, nil
+	case protocolGRPCWebText:
+		if body, err = decodeText(body); err != nil {
+			return nil, err
+		}
+	}
+	return readEnvelopes(body)
+}
+
+// timeout parses the grpc-timeout or Connect-Timeout-Ms header
+func timeout(r *http.Request) time.Duration {
+	if v := r.Header.Get("Connect-Timeout-Ms"); len(v) > 0 {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || ms <= 0 {
+			return 0
+		}
+		return time.Duration(ms) * time.Millisecond
+	}
+
+	v := r.Header.Get("Grpc-Timeout")
+	if len(v) < 2 {
+		return 0
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n <= 0 {
+		return 0
+	}
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+	unit, ok := units[v[len(v)-1]]
+	if !ok {
+		return 0
+	}
+	return time.Duration(n) * unit
+}
+
+// microError converts the error returned by the client to an errors.Error. Errors
+// returned when streaming are grpc statuses which carry the error as a detail.
+func microError(err error) *errors.Error {
+	if verr, ok := err.(*errors.Error); ok {
+		return verr
+	}
+	if s, ok := status.FromError(err); ok {
+		for _, d := range s.Details() {
+			if verr, ok := d.(*errors.Error); ok {
+				return verr
+			}
+		}
+		if verr := errors.Parse(s.Message()); verr.Code > 0 {
+			return verr
+		}
+		return &errors.Error{Id: "go.micro.api", Code: httpCode(s.Code()), Detail: s.Message(), Status: http.StatusText(int(httpCode(s.Code())))}
+	}
+	return errors.InternalServerError("go.micro.api", err.Error()).(*errors.Error)
+}
+
+// grpcCode maps the code of an errors.Error to a grpc code, the same as the grpc server
+func grpcCode(err *errors.Error) codes.Code {
+	switch err.Code {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusRequestTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusInternalServerError:
+		return codes.Internal
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	}
+	return codes.Unknown
+}
+
+// httpCode maps a grpc code to the http status used by the connect protocol
+func httpCode(c codes.Code) int32 {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
+
+// connectCodes are the names of the grpc codes in the connect protocol
+var connectCodes = map[codes.Code]string{
+	codes.Canceled:           "canceled",
+	codes.Unknown:            "unknown",
+	codes.InvalidArgument:    "invalid_argument",
+	codes.DeadlineExceeded:   "deadline_exceeded",
+	codes.NotFound:           "not_found",
+	codes.AlreadyExists:      "already_exists",
+	codes.PermissionDenied:   "permission_denied",
+	codes.ResourceExhausted:  "resource_exhausted",
+	codes.FailedPrecondition: "failed_precondition",
+	codes.Aborted:            "aborted",
+	codes.OutOfRange:         "out_of_range",
+	codes.Unimplemented:      "unimplemented",
+	codes.Internal:           "internal",
+	codes.Unavailable:        "unavailable",
+	codes.DataLoss:           "data_loss",
+	codes.Unauthenticated:    "unauthenticated",
+}
+
+// trailers returns the grpc-web trailers of the error, the errors.Error is
+// attached as a detail of the status like the grpc server does
+func trailers(err *errors.Error) []byte {
+	if err == nil {
+		return []byte("grpc-status: 0\r\ngrpc-message: \r\n")
+	}
+
+	code := grpcCode(err)
+	b := fmt.Sprintf("grpc-status: %d\r\ngrpc-message: %s\r\n", code, url.PathEscape(err.Detail))
+
+	if s, serr := status.New(code, err.Detail).WithDetails(err); serr == nil {
+		if pb, merr := proto.Marshal(s.Proto()); merr == nil {
+			b += fmt.Sprintf("grpc-status-details-bin: %s\r\n", base64.RawStdEncoding.EncodeToString(pb))
+		}
+	}
+	return []byte(b)
+}
+
+// connectError is the json error of the connect protocol
+type connectError struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message,omitempty"`
+	Details []connectDetail `json:"details,omitempty"`
+}
+
+type connectDetail struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// newConnectError returns the connect error with the errors.Error as a detail
+func newConnectError(err *errors.Error) *connectError {
+	ce := &connectError{Code: connectCodes[grpcCode(err)], Message: err.Detail}
+	if len(ce.Code) == 0 {
+		ce.Code = "unknown"
+	}
+	if b, merr := proto.Marshal(err); merr == nil {
+		ce.Details = append(ce.Details, connectDetail{
+			Type:  string(proto.MessageName(err)),
+			Value: base64.RawStdEncoding.EncodeToString(b),
+		})
+	}
+	return ce
+}
+
+// endStream returns the message ending a connect stream
+func endStream(err *errors.Error) []byte {
+	var end struct {
+		Error *connectError `json:"error,omitempty"`
+	}
+	if err != nil {
+		end.Error = newConnectError(err)
+	}
+	b, _ := json.Marshal(end)
+	return b
+}
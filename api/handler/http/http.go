@@ -25,6 +25,10 @@ type httpHandler struct {
 }
 
 func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !handler.Authenticate(w, r, h.options) {
+		return
+	}
+
 	service, err := h.getService(r)
 	if err != nil {
 		w.WriteHeader(500)
@@ -4,6 +4,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"c-z.dev/go-micro/api/handler"
@@ -11,8 +12,12 @@ import (
 	"c-z.dev/go-micro/api/resolver/vpath"
 	"c-z.dev/go-micro/api/router"
 	regRouter "c-z.dev/go-micro/api/router/registry"
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/apikey"
+	authstore "c-z.dev/go-micro/auth/store"
 	"c-z.dev/go-micro/registry"
 	"c-z.dev/go-micro/registry/memory"
+	mstore "c-z.dev/go-micro/store/memory"
 )
 
 func testHttp(t *testing.T, path, service, ns string) {
@@ -125,3 +130,92 @@ func TestHttpHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestHttpHandlerAPIKey(t *testing.T) {
+	r := memory.NewRegistry()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	s := &registry.Service{
+		Name: "go.micro.api.test",
+		Nodes: []*registry.Node{
+			{
+				Id:      "go.micro.api.test-1",
+				Address: l.Addr().String(),
+			},
+		},
+	}
+
+	r.Register(s)
+	defer r.Deregister(s)
+
+	// the service gets a token instead of the key
+	m := http.NewServeMux()
+	m.HandleFunc("/test/foo", func(w http.ResponseWriter, r *http.Request) {
+		if len(r.Header.Get(handler.APIKeyHeader)) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization")))
+	})
+	go http.Serve(l, m)
+
+	rt := regRouter.NewRouter(
+		router.WithHandler("http"),
+		router.WithRegistry(r),
+		router.WithResolver(vpath.NewResolver(
+			resolver.WithNamespace(resolver.StaticNamespace("go.micro.api")),
+		)),
+	)
+
+	st := mstore.NewStore()
+	a := authstore.NewAuth(auth.Store(st), auth.Namespace("micro"))
+	acc, err := a.Generate("john", auth.WithScopes("read"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := apikey.NewKeys(apikey.WithStore(st), apikey.WithAuth(a))
+	_, key, err := keys.Create(acc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewHandler(handler.WithRouter(rt), handler.WithAPIKeys(keys))
+
+	tt := []struct {
+		Name string
+		Key  string
+		Code int
+	}{
+		{Name: "ValidKey", Key: key, Code: http.StatusOK},
+		{Name: "InvalidKey", Key: key + "x", Code: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest("POST", "/test/foo", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set(handler.APIKeyHeader, tc.Key)
+
+			p.ServeHTTP(w, req)
+
+			if w.Code != tc.Code {
+				t.Fatalf("Expected %d response got %d %s", tc.Code, w.Code, w.Body.String())
+			}
+			if tc.Code != http.StatusOK {
+				return
+			}
+			tok := strings.TrimPrefix(w.Body.String(), auth.BearerScheme)
+			if tok == w.Body.String() || len(tok) == 0 {
+				t.Fatalf("Expected bearer token got %s", w.Body.String())
+			}
+		})
+	}
+}
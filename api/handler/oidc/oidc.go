@@ -0,0 +1,209 @@
+// Package oidc is an api handler which logs users in with an OpenID Connect provider
+package oidc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"c-z.dev/go-micro/api/handler"
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/provider"
+	"c-z.dev/go-micro/auth/provider/oidc"
+	authstore "c-z.dev/go-micro/auth/store"
+)
+
+const (
+	Handler = "oidc"
+)
+
+var (
+	// StateCookie holds the login state between the redirect and the callback
+	StateCookie = "micro-oidc"
+	// TokenCookie is set to the access token after logging in
+	TokenCookie = "micro-token"
+	// StateExpiry is how long a login can take
+	StateExpiry = time.Minute * 10
+)
+
+type oidcHandler struct {
+	opts     handler.Options
+	provider oidc.Provider
+	auth     auth.Auth
+}
+
+// state is kept in a cookie during the login
+type state struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Redirect string `json:"redirect"`
+}
+
+// ServeHTTP starts the login by redirecting to the issuer and completes
+// it when the issuer redirects back with an authorization code. After
+// logging in the user is redirected to the relative url passed as the
+// redirect_to param of the first request, or the token is returned as json.
+func (h *oidcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.provider == nil {
+		http.Error(w, "no oidc provider", http.StatusInternalServerError)
+		return
+	}
+
+	q := r.URL.Query()
+	if len(q.Get("code")) > 0 || len(q.Get("error")) > 0 {
+		h.callback(w, r)
+		return
+	}
+
+	if _, err := h.provider.Discover(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	st := &state{
+		State:    oidc.NewVerifier(),
+		Nonce:    oidc.NewVerifier(),
+		Verifier: oidc.NewVerifier(),
+	}
+	if redir := q.Get("redirect_to"); isRelative(redir) {
+		st.Redirect = redir
+	}
+
+	b, _ := json.Marshal(st)
+	http.SetCookie(w, &http.Cookie{
+		Name:     StateCookie,
+		Value:    base64.RawURLEncoding.EncodeToString(b),
+		Path:     r.URL.Path,
+		MaxAge:   int(StateExpiry.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, h.provider.Endpoint(
+		provider.WithState(st.State),
+		provider.WithNonce(st.Nonce),
+		provider.WithCodeChallenge(oidc.Challenge(st.Verifier)),
+		provider.WithLoginHint(q.Get("login_hint")),
+	), http.StatusFound)
+}
+
+func (h *oidcHandler) callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	// the state is only used once
+	http.SetCookie(w, &http.Cookie{Name: StateCookie, Path: r.URL.Path, MaxAge: -1})
+
+	if e := q.Get("error"); len(e) > 0 {
+		http.Error(w, "login failed: "+e, http.StatusUnauthorized)
+		return
+	}
+
+	c, err := r.Cookie(StateCookie)
+	if err != nil {
+		http.Error(w, "login state not found", http.StatusBadRequest)
+		return
+	}
+	var st state
+	b, err := base64.RawURLEncoding.DecodeString(c.Value)
+	if err == nil {
+		err = json.Unmarshal(b, &st)
+	}
+	if err != nil || len(st.State) == 0 || st.State != q.Get("state") {
+		http.Error(w, "invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.provider.Exchange(r.Context(), q.Get("code"), st.Verifier)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := h.provider.Verify(r.Context(), tokens.IDToken, st.Nonce)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// issue a micro token for the account
+	tok, err := h.login(h.provider.Account(claims))
+	if err == authstore.ErrAccountExists {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(st.Redirect) == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tok)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     TokenCookie,
+		Value:    tok.AccessToken,
+		Path:     "/",
+		Expires:  tok.Expiry,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, st.Redirect, http.StatusFound)
+}
+
+// login issues a token to the account of the provider. The subject is namespaced
+// by its issuer so it can't collide with other accounts. The store auth reuses the
+// account and never replaces accounts of other providers, other auths regenerate it.
+func (h *oidcHandler) login(acc *auth.Account) (*auth.Token, error) {
+	id := acc.Issuer + "|" + acc.ID
+	opts := []auth.GenerateOption{
+		auth.WithType(acc.Type),
+		auth.WithScopes(acc.Scopes...),
+		auth.WithMetadata(acc.Metadata),
+		auth.WithProvider(h.provider.String()),
+	}
+
+	if f, ok := h.auth.(authstore.Federator); ok {
+		return f.Federate(id, opts...)
+	}
+
+	secret := oidc.NewVerifier()
+	if _, err := h.auth.Generate(id, append(opts, auth.WithSecret(secret))...); err != nil {
+		return nil, err
+	}
+	return h.auth.Token(auth.WithCredentials(id, secret))
+}
+
+func (h *oidcHandler) String() string {
+	return Handler
+}
+
+// isRelative only allows redirects within the site
+func isRelative(u string) bool {
+	return strings.HasPrefix(u, "/") && !strings.HasPrefix(u, "//") && !strings.HasPrefix(u, "/\\")
+}
+
+// NewHandler returns a handler which logs users in with the provider set by WithProvider
+func NewHandler(opts ...handler.Option) handler.Handler {
+	options := handler.NewOptions(opts...)
+
+	h := &oidcHandler{
+		opts: options,
+		auth: auth.DefaultAuth,
+	}
+	if c := options.Context; c != nil {
+		if p, ok := c.Value(providerKey{}).(oidc.Provider); ok {
+			h.provider = p
+		}
+		if a, ok := c.Value(authKey{}).(auth.Auth); ok && a != nil {
+			h.auth = a
+		}
+	}
+	return h
+}
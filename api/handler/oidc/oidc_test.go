@@ -0,0 +1,218 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/provider"
+	"c-z.dev/go-micro/auth/provider/oidc"
+	authstore "c-z.dev/go-micro/auth/store"
+	"c-z.dev/go-micro/auth/token/jwt"
+	"c-z.dev/go-micro/store/memory"
+)
+
+// newIssuer returns a fake issuer which logs everyone in as user-1
+func newIssuer(t *testing.T) *httptest.Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := make(map[string]url.Values)
+
+	var s *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&oidc.Discovery{
+			Issuer:                s.URL,
+			AuthorizationEndpoint: s.URL + "/authorize",
+			TokenEndpoint:         s.URL + "/token",
+			JWKSURI:               s.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []*jwt.JWK{{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		code := oidc.NewVerifier()
+		requests[code] = q
+		http.Redirect(w, r, q.Get("redirect_uri")+"?code="+code+"&state="+url.QueryEscape(q.Get("state")), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		q, ok := requests[r.Form.Get("code")]
+		if !ok || oidc.Challenge(r.Form.Get("code_verifier")) != q.Get("code_challenge") {
+			http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		h, _ := json.Marshal(map[string]string{"alg": "RS256"})
+		c, _ := json.Marshal(map[string]interface{}{
+			"iss":   s.URL,
+			"sub":   "user-1",
+			"aud":   q.Get("client_id"),
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": q.Get("nonce"),
+		})
+		signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+		d := sha256.Sum256([]byte(signed))
+		sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, d[:])
+		json.NewEncoder(w).Encode(&oidc.Tokens{IDToken: signed + "." + base64.RawURLEncoding.EncodeToString(sig)})
+	})
+
+	s = httptest.NewServer(mux)
+	return s
+}
+
+func TestOIDCHandler(t *testing.T) {
+	is := newIssuer(t)
+	defer is.Close()
+
+	a := authstore.NewAuth(auth.Store(memory.NewStore()))
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.Handle("/login", NewHandler(
+		WithAuth(a),
+		WithProvider(oidc.NewProvider(
+			provider.Endpoint(is.URL),
+			provider.Credentials("micro", ""),
+			provider.Redirect(srv.URL+"/login"),
+		)),
+	))
+	mux.HandleFunc("/home", func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie(TokenCookie)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		acc, err := a.Inspect(c.Value)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(acc.ID))
+	})
+
+	jar, _ := cookiejar.New(nil)
+	c := &http.Client{Jar: jar}
+
+	rsp, err := c.Get(srv.URL + "/login?redirect_to=/home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK || rsp.Request.URL.Path != "/home" {
+		t.Fatalf("Expected to be logged in and redirected home but got %s %s", rsp.Status, rsp.Request.URL)
+	}
+
+	// without a redirect the token is returned
+	rsp, err = c.Get(srv.URL + "/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tok *auth.Token
+	if err := json.NewDecoder(rsp.Body).Decode(&tok); err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	// the subject is namespaced by the issuer
+	id := is.URL + "|user-1"
+	if acc, err := a.Inspect(tok.AccessToken); err != nil || acc.ID != id {
+		t.Fatalf("Expected token for %s got %v %v", id, acc, err)
+	}
+
+	// logging in again reuses the account and keeps the other sessions
+	rsp, err = c.Get(srv.URL + "/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if _, err := a.Token(auth.WithToken(tok.RefreshToken)); err != nil {
+		t.Fatalf("Expected the first session to be valid got %v", err)
+	}
+
+	// a callback without the login state is rejected
+	rsp, err = http.Get(srv.URL + "/login?code=foo&state=bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected bad request got %s", rsp.Status)
+	}
+
+	// a null login state is rejected
+	req, _ := http.NewRequest("GET", srv.URL+"/login?code=foo&state=bar", nil)
+	req.AddCookie(&http.Cookie{Name: StateCookie, Value: base64.RawURLEncoding.EncodeToString([]byte("null"))})
+	rsp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected bad request got %s", rsp.Status)
+	}
+}
+
+func TestOIDCHandlerExistingAccount(t *testing.T) {
+	is := newIssuer(t)
+	defer is.Close()
+
+	a := authstore.NewAuth(auth.Store(memory.NewStore()))
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.Handle("/login", NewHandler(
+		WithAuth(a),
+		WithProvider(oidc.NewProvider(
+			provider.Endpoint(is.URL),
+			provider.Credentials("micro", ""),
+			provider.Redirect(srv.URL+"/login"),
+		)),
+	))
+
+	// accounts of other providers are never replaced
+	id := is.URL + "|user-1"
+	if _, err := a.Generate(id, auth.WithSecret("password"), auth.WithScopes("admin"), auth.WithType("service")); err != nil {
+		t.Fatal(err)
+	}
+
+	jar, _ := cookiejar.New(nil)
+	c := &http.Client{Jar: jar}
+	rsp, err := c.Get(srv.URL + "/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected forbidden got %s", rsp.Status)
+	}
+
+	tok, err := a.Token(auth.WithCredentials(id, "password"))
+	if err != nil {
+		t.Fatalf("Expected the account to be unchanged got %v", err)
+	}
+	if acc, err := a.Inspect(tok.AccessToken); err != nil || acc.Type != "service" {
+		t.Fatalf("Expected the service account got %v %v", acc, err)
+	}
+}
@@ -0,0 +1,31 @@
+package oidc
+
+import (
+	"context"
+
+	"c-z.dev/go-micro/api/handler"
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/provider/oidc"
+)
+
+type providerKey struct{}
+type authKey struct{}
+
+// WithProvider sets the OpenID Connect provider users log in with
+func WithProvider(p oidc.Provider) handler.Option {
+	return setOption(providerKey{}, p)
+}
+
+// WithAuth sets the auth which issues tokens, it defaults to auth.DefaultAuth
+func WithAuth(a auth.Auth) handler.Option {
+	return setOption(authKey{}, a)
+}
+
+func setOption(k, v interface{}) handler.Option {
+	return func(o *handler.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
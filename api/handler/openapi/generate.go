@@ -0,0 +1,346 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"c-z.dev/go-micro/api"
+	"c-z.dev/go-micro/api/handler"
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/rules"
+	"c-z.dev/go-micro/registry"
+)
+
+var (
+	// params matches the variables of a path template e.g. {name} or {name=**}
+	params = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?\}`)
+
+	// errorSchema is the json encoded errors.Error returned by the handlers
+	errorSchema = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id":     {Type: "string"},
+			"code":   {Type: "integer", Format: "int32"},
+			"detail": {Type: "string"},
+			"status": {Type: "string"},
+		},
+	}
+)
+
+const (
+	schemeBearer = "bearer"
+	schemeAPIKey = "apiKey"
+)
+
+type generator struct {
+	doc   *Document
+	rules []*auth.Rule
+	ids   map[string]bool
+}
+
+// Generate an OpenAPI document for the api services, usually the ones listed by a
+// router.Lister. The schemas are derived from the request and response values the
+// services registered. If rules are passed the operations which aren't public require
+// a bearer token or api key, with the scopes which grant access listed as x-micro-scopes.
+// Endpoints routed with a regular expression can't be described and are skipped.
+func Generate(info *Info, services []*api.Service, rs []*auth.Rule) *Document {
+	g := &generator{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   make(map[string]*PathItem),
+			Components: &Components{
+				Schemas: map[string]*Schema{"Error": errorSchema},
+			},
+		},
+		rules: rs,
+		ids:   make(map[string]bool),
+	}
+
+	if rs != nil {
+		g.doc.Components.SecuritySchemes = map[string]*SecurityScheme{
+			schemeBearer: {Type: "http", Scheme: "bearer"},
+			schemeAPIKey: {Type: "apiKey", In: "header", Name: handler.APIKeyHeader},
+		}
+	}
+
+	for _, s := range services {
+		if s == nil || s.Endpoint == nil {
+			continue
+		}
+		ep := endpoint(s)
+
+		for _, p := range s.Endpoint.Path {
+			path, vars, ok := template(p)
+			if !ok {
+				continue
+			}
+			item, ok := g.doc.Paths[path]
+			if !ok {
+				item = &PathItem{}
+			}
+			var added bool
+			for _, m := range s.Endpoint.Method {
+				if op := operation(item, m); op != nil {
+					*op = g.operation(s, ep, vars, m)
+					added = true
+				}
+			}
+			if added {
+				g.doc.Paths[path] = item
+			}
+		}
+	}
+
+	return g.doc
+}
+
+// operation describes an endpoint called with the method
+func (g *generator) operation(s *api.Service, ep *registry.Endpoint, vars []string, method string) *Operation {
+	op := &Operation{
+		OperationID: g.id(s.Name + "." + s.Endpoint.Name),
+		Summary:     s.Endpoint.Description,
+		Tags:        []string{s.Name},
+		Stream:      s.Endpoint.Stream,
+		Responses: map[string]*Response{
+			"200":     {Description: "OK"},
+			"default": {Description: "Error", Content: jsonContent(&Schema{Ref: ref("Error")})},
+		},
+	}
+
+	var req, rsp *registry.Value
+	if ep != nil {
+		req, rsp = ep.Request, ep.Response
+		op.Stream = op.Stream || ep.Metadata["stream"] == "true"
+	}
+
+	for _, v := range vars {
+		schema := &Schema{Type: "string"}
+		if f := field(req, v); f != nil {
+			schema = g.schema(f)
+		}
+		op.Parameters = append(op.Parameters, &Parameter{Name: v, In: "path", Required: true, Schema: schema})
+	}
+
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodDelete, http.MethodHead:
+		// the remaining fields of the request are passed in the query
+		if req != nil {
+			for _, f := range req.Values {
+				if skip(f) || contains(vars, f.Name) || len(f.Values) > 0 {
+					continue
+				}
+				op.Parameters = append(op.Parameters, &Parameter{Name: f.Name, In: "query", Schema: g.schema(f)})
+			}
+		}
+	default:
+		body := req
+		if b := s.Endpoint.Body; len(b) > 0 && b != "*" {
+			body = field(req, b)
+		}
+		if body != nil {
+			op.RequestBody = &RequestBody{Content: jsonContent(g.schema(body))}
+		}
+	}
+
+	if rsp != nil {
+		op.Responses["200"].Content = jsonContent(g.schema(rsp))
+	}
+
+	if g.rules != nil {
+		g.security(op, &auth.Resource{Type: "service", Name: s.Name, Endpoint: s.Endpoint.Name})
+	}
+
+	return op
+}
+
+// security sets the auth requirements of the operation from the rules
+func (g *generator) security(op *Operation, res *auth.Resource) {
+	if rules.Verify(g.rules, nil, res) == nil {
+		return
+	}
+	op.Security = []map[string][]string{
+		{schemeBearer: {}},
+		{schemeAPIKey: {}},
+	}
+
+	// any account has access
+	if rules.Verify(g.rules, &auth.Account{}, res) == nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range g.rules {
+		if r.Scope == auth.ScopePublic || r.Scope == auth.ScopeAccount || seen[r.Scope] {
+			continue
+		}
+		seen[r.Scope] = true
+		if rules.Verify(g.rules, &auth.Account{Scopes: []string{r.Scope}}, res) == nil {
+			op.Scopes = append(op.Scopes, r.Scope)
+		}
+	}
+	sort.Strings(op.Scopes)
+}
+
+// schema of a value, structs are added to the components and referenced
+func (g *generator) schema(v *registry.Value) *Schema {
+	if strings.HasPrefix(v.Type, "[]") {
+		elem := strings.TrimPrefix(v.Type, "[]")
+		if elem == "uint8" || elem == "byte" {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		if _, ok := g.doc.Components.Schemas[elem]; ok {
+			return &Schema{Type: "array", Items: &Schema{Ref: ref(elem)}}
+		}
+		return &Schema{Type: "array", Items: scalar(elem)}
+	}
+
+	if len(v.Values) == 0 {
+		return scalar(v.Type)
+	}
+
+	s, ok := g.doc.Components.Schemas[v.Type]
+	if !ok {
+		s = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		if len(v.Type) > 0 {
+			// added before the properties in case the type is recursive
+			g.doc.Components.Schemas[v.Type] = s
+		}
+	}
+	// values are only extracted to a limited depth, so the
+	// same type may have more fields elsewhere
+	for _, f := range v.Values {
+		if skip(f) {
+			continue
+		}
+		if _, ok := s.Properties[f.Name]; !ok {
+			s.Properties[f.Name] = g.schema(f)
+		}
+	}
+
+	if len(v.Type) == 0 {
+		return s
+	}
+	return &Schema{Ref: ref(v.Type)}
+}
+
+// id returns a unique operation id
+func (g *generator) id(name string) string {
+	id := name
+	for i := 2; g.ids[id]; i++ {
+		id = fmt.Sprintf("%s_%d", name, i)
+	}
+	g.ids[id] = true
+	return id
+}
+
+// scalar returns the schema of a go type
+func scalar(t string) *Schema {
+	switch t {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "int64", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}
+	case "Time":
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	return &Schema{Type: "object", AdditionalProperties: true}
+}
+
+// endpoint returns the registered endpoint of the api service
+func endpoint(s *api.Service) *registry.Endpoint {
+	for _, svc := range s.Services {
+		for _, ep := range svc.Endpoints {
+			if ep.Name == s.Endpoint.Name {
+				return ep
+			}
+		}
+	}
+	return nil
+}
+
+// field returns the value of a field of the request, nested fields are separated by dots
+func field(v *registry.Value, name string) *registry.Value {
+	for _, part := range strings.Split(name, ".") {
+		if v == nil {
+			return nil
+		}
+		var found *registry.Value
+		for _, f := range v.Values {
+			if f.Name == part {
+				found = f
+				break
+			}
+		}
+		v = found
+	}
+	return v
+}
+
+// skip fields without a json name, e.g. the internal fields of protobuf messages
+func skip(f *registry.Value) bool {
+	return f == nil || len(f.Name) == 0 || f.Name == f.Type || f.Name == "unknownFields"
+}
+
+// template converts a path template to an OpenAPI path and returns its variables
+func template(p string) (string, []string, bool) {
+	if len(p) == 0 || p[0] == '^' {
+		return "", nil, false
+	}
+	var vars []string
+	path := params.ReplaceAllStringFunc(p, func(m string) string {
+		name := params.FindStringSubmatch(m)[1]
+		vars = append(vars, name)
+		return "{" + name + "}"
+	})
+	return path, vars, true
+}
+
+// operation returns the operation of the path for the method
+func operation(item *PathItem, method string) **Operation {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		return &item.Get
+	case http.MethodPut:
+		return &item.Put
+	case http.MethodPost:
+		return &item.Post
+	case http.MethodDelete:
+		return &item.Delete
+	case http.MethodOptions:
+		return &item.Options
+	case http.MethodHead:
+		return &item.Head
+	case http.MethodPatch:
+		return &item.Patch
+	}
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonContent(s *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: s}}
+}
+
+func ref(name string) string {
+	return "#/components/schemas/" + name
+}
@@ -0,0 +1,81 @@
+// Package openapi is an api handler which serves an OpenAPI 3 document of the routed services
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"c-z.dev/go-micro/api/handler"
+	"c-z.dev/go-micro/api/router"
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/errors"
+)
+
+const (
+	Handler = "openapi"
+)
+
+type openapiHandler struct {
+	opts handler.Options
+	auth auth.Auth
+	info *Info
+}
+
+// ServeHTTP generates the document from the services the router currently
+// routes to, so it changes as services come and go
+func (h *openapiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l, ok := h.opts.Router.(router.Lister)
+	if !ok {
+		h.error(w, "router can't list services")
+		return
+	}
+
+	var rs []*auth.Rule
+	if h.auth != nil {
+		var err error
+		if rs, err = h.auth.Rules(); err != nil {
+			h.error(w, err.Error())
+			return
+		}
+	}
+
+	b, err := json.Marshal(Generate(h.info, l.Services(), rs))
+	if err != nil {
+		h.error(w, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+func (h *openapiHandler) String() string {
+	return Handler
+}
+
+func (h *openapiHandler) error(w http.ResponseWriter, detail string) {
+	er := errors.InternalServerError(h.opts.Namespace, detail)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	w.Write([]byte(er.Error()))
+}
+
+// NewHandler returns a handler serving the OpenAPI document of the services
+// routed to by the router set with handler.WithRouter
+func NewHandler(opts ...handler.Option) handler.Handler {
+	options := handler.NewOptions(opts...)
+
+	h := &openapiHandler{
+		opts: options,
+		info: &Info{Title: options.Namespace, Version: "latest"},
+	}
+	if c := options.Context; c != nil {
+		if a, ok := c.Value(authKey{}).(auth.Auth); ok {
+			h.auth = a
+		}
+		if i, ok := c.Value(infoKey{}).(*Info); ok && i != nil {
+			h.info = i
+		}
+	}
+	return h
+}
@@ -0,0 +1,196 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/api"
+	"c-z.dev/go-micro/api/handler"
+	"c-z.dev/go-micro/api/router"
+	regRouter "c-z.dev/go-micro/api/router/registry"
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/registry"
+	"c-z.dev/go-micro/registry/memory"
+)
+
+func testService() *registry.Service {
+	return &registry.Service{
+		Name:    "go.micro.api.notes",
+		Version: "latest",
+		Endpoints: []*registry.Endpoint{
+			{
+				Name: "Notes.Read",
+				Request: &registry.Value{Name: "ReadRequest", Type: "ReadRequest", Values: []*registry.Value{
+					{Name: "MessageState", Type: "MessageState"},
+					{Name: "id", Type: "string"},
+					{Name: "limit", Type: "int32"},
+				}},
+				Response: &registry.Value{Name: "ReadResponse", Type: "ReadResponse", Values: []*registry.Value{
+					{Name: "note", Type: "Note", Values: []*registry.Value{
+						{Name: "id", Type: "string"},
+						{Name: "tags", Type: "[]string"},
+						{Name: "data", Type: "[]uint8"},
+						{Name: "created", Type: "int64"},
+					}},
+				}},
+				Metadata: api.Encode(&api.Endpoint{
+					Name:        "Notes.Read",
+					Description: "Read a note",
+					Handler:     "rpc",
+					Method:      []string{"GET"},
+					Path:        []string{"/notes/{id=*}"},
+				}),
+			},
+			{
+				Name: "Notes.Create",
+				Request: &registry.Value{Name: "CreateRequest", Type: "CreateRequest", Values: []*registry.Value{
+					{Name: "note", Type: "Note", Values: []*registry.Value{
+						{Name: "id", Type: "string"},
+					}},
+				}},
+				Metadata: api.Encode(&api.Endpoint{
+					Name:    "Notes.Create",
+					Handler: "rpc",
+					Method:  []string{"POST", "PUT"},
+					Path:    []string{"/notes", "^/v1/notes$"},
+				}),
+			},
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	svc := testService()
+	var services []*api.Service
+	for _, ep := range svc.Endpoints {
+		services = append(services, &api.Service{
+			Name:     svc.Name,
+			Endpoint: api.Decode(ep.Metadata),
+			Services: []*registry.Service{svc},
+		})
+	}
+
+	all := &auth.Resource{Type: "*", Name: "*", Endpoint: "*"}
+	rules := []*auth.Rule{
+		{ID: "read", Scope: auth.ScopePublic, Resource: &auth.Resource{Type: "service", Name: svc.Name, Endpoint: "Notes.Read"}},
+		{ID: "writers", Scope: "notes.write", Resource: all},
+		{ID: "admins", Scope: "admin", Resource: all},
+	}
+
+	doc := Generate(&Info{Title: "Notes", Version: "v1"}, services, rules)
+	if doc.OpenAPI != Version || doc.Info.Title != "Notes" {
+		t.Fatalf("Unexpected document %+v", doc)
+	}
+
+	// regular expression paths are skipped
+	if len(doc.Paths) != 2 {
+		t.Fatalf("Expected 2 paths but got %v", len(doc.Paths))
+	}
+
+	read := doc.Paths["/notes/{id}"]
+	if read == nil || read.Get == nil {
+		t.Fatalf("Expected GET /notes/{id} but got %+v", doc.Paths)
+	}
+	if read.Get.OperationID != "go.micro.api.notes.Notes.Read" || read.Get.Summary != "Read a note" {
+		t.Errorf("Unexpected operation %+v", read.Get)
+	}
+	if len(read.Get.Security) != 0 {
+		t.Errorf("Expected public operation but got %v", read.Get.Security)
+	}
+	// internal fields aren't parameters
+	if p := read.Get.Parameters; len(p) != 2 || p[0].Name != "id" || p[0].In != "path" || p[1].Name != "limit" || p[1].In != "query" {
+		t.Errorf("Unexpected parameters %+v", p)
+	}
+	if s := read.Get.Responses["200"].Content["application/json"].Schema; s.Ref != "#/components/schemas/ReadResponse" {
+		t.Errorf("Unexpected response schema %+v", s)
+	}
+
+	create := doc.Paths["/notes"]
+	if create == nil || create.Post == nil || create.Put == nil {
+		t.Fatalf("Expected POST and PUT /notes but got %+v", create)
+	}
+	if create.Post.OperationID == create.Put.OperationID {
+		t.Errorf("Expected unique operation ids")
+	}
+	if create.Post.RequestBody == nil || create.Post.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/CreateRequest" {
+		t.Errorf("Unexpected request body %+v", create.Post.RequestBody)
+	}
+	if len(create.Post.Security) != 2 {
+		t.Errorf("Expected protected operation but got %v", create.Post.Security)
+	}
+	if s := create.Post.Scopes; len(s) != 2 || s[0] != "admin" || s[1] != "notes.write" {
+		t.Errorf("Unexpected scopes %v", s)
+	}
+
+	// the note fields found at different depths are merged
+	note := doc.Components.Schemas["Note"]
+	if note == nil {
+		t.Fatal("Expected Note schema")
+	}
+	expected := map[string]*Schema{
+		"id":      {Type: "string"},
+		"tags":    {Type: "array", Items: &Schema{Type: "string"}},
+		"data":    {Type: "string", Format: "byte"},
+		"created": {Type: "integer", Format: "int64"},
+	}
+	for name, s := range expected {
+		b1, _ := json.Marshal(note.Properties[name])
+		b2, _ := json.Marshal(s)
+		if string(b1) != string(b2) {
+			t.Errorf("Expected %v to be %s but got %s", name, b2, b1)
+		}
+	}
+	if _, ok := doc.Components.SecuritySchemes["apiKey"]; !ok {
+		t.Errorf("Expected api key security scheme")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	reg := memory.NewRegistry()
+	rt := regRouter.NewRouter(router.WithRegistry(reg))
+	defer rt.Close()
+
+	h := NewHandler(handler.WithRouter(rt), WithInfo(&Info{Title: "Notes", Version: "v1"}))
+
+	get := func() *Document {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/openapi.json", nil)
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200 but got %v %s", w.Code, w.Body.String())
+		}
+		var doc *Document
+		if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc
+	}
+
+	if doc := get(); len(doc.Paths) != 0 {
+		t.Fatalf("Expected no paths but got %v", len(doc.Paths))
+	}
+
+	// the document is updated as services are registered
+	svc := testService()
+	svc.Nodes = []*registry.Node{{Id: "notes-1", Address: "127.0.0.1:8080"}}
+	if err := reg.Register(svc); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc *Document
+	for i := 0; i < 20; i++ {
+		if doc = get(); len(doc.Paths) == 2 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if len(doc.Paths) != 2 {
+		t.Fatalf("Expected 2 paths but got %v", len(doc.Paths))
+	}
+	if doc.Components.SecuritySchemes != nil {
+		t.Errorf("Expected no security without auth")
+	}
+}
@@ -0,0 +1,30 @@
+package openapi
+
+import (
+	"context"
+
+	"c-z.dev/go-micro/api/handler"
+	"c-z.dev/go-micro/auth"
+)
+
+type authKey struct{}
+type infoKey struct{}
+
+// WithAuth sets the auth whose rules determine the security of the operations
+func WithAuth(a auth.Auth) handler.Option {
+	return setOption(authKey{}, a)
+}
+
+// WithInfo sets the title, description and version of the api
+func WithInfo(i *Info) handler.Option {
+	return setOption(infoKey{}, i)
+}
+
+func setOption(k, v interface{}) handler.Option {
+	return func(o *handler.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"context"
+
 	"c-z.dev/go-micro/api/router"
+	"c-z.dev/go-micro/auth/apikey"
 	"c-z.dev/go-micro/client"
 	"c-z.dev/go-micro/client/grpc"
 )
@@ -13,6 +16,10 @@ type Options struct {
 	Namespace   string
 	Router      router.Router
 	Client      client.Client
+	// APIKeys verifies the keys passed in the X-API-Key header
+	APIKeys apikey.Keys
+	// Context for other opts
+	Context context.Context
 }
 
 type Option func(o *Options)
@@ -60,6 +67,13 @@ func WithClient(c client.Client) Option {
 	}
 }
 
+// WithAPIKeys verifies the api keys passed in the X-API-Key header
+func WithAPIKeys(k apikey.Keys) Option {
+	return func(o *Options) {
+		o.APIKeys = k
+	}
+}
+
 // WithmaxRecvSize specifies max body size
 func WithMaxRecvSize(size int64) Option {
 	return func(o *Options) {
@@ -26,6 +26,10 @@ type webHandler struct {
 }
 
 func (wh *webHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !handler.Authenticate(w, r, wh.opts) {
+		return
+	}
+
 	service, err := wh.getService(r)
 	if err != nil {
 		w.WriteHeader(500)
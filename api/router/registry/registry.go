@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -409,6 +410,24 @@ func (r *registryRouter) Endpoint(req *http.Request) (*api.Service, error) {
 	return nil, errors.New("not found")
 }
 
+// Services returns the endpoints currently routed to, ordered by service and endpoint name
+func (r *registryRouter) Services() []*api.Service {
+	r.RLock()
+	defer r.RUnlock()
+
+	keys := make([]string, 0, len(r.eps))
+	for k := range r.eps {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	services := make([]*api.Service, 0, len(keys))
+	for _, k := range keys {
+		services = append(services, r.eps[k])
+	}
+	return services
+}
+
 func (r *registryRouter) Route(req *http.Request) (*api.Service, error) {
 	if r.isClosed() {
 		return nil, errors.New("router closed")
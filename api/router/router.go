@@ -22,3 +22,9 @@ type Router interface {
 	// Route returns an api.Service route
 	Route(r *http.Request) (*api.Service, error)
 }
+
+// Lister is implemented by routers which can list the services they route to
+type Lister interface {
+	// Services returns the api.Service of each routed endpoint
+	Services() []*api.Service
+}
@@ -0,0 +1,577 @@
+// Package apikey provides long lived api keys for third party access. Keys are
+// bound to an account and a subset of its scopes, the requests made with a key
+// are forwarded with a short lived token issued by the auth to an account of the
+// key. The account has the id of the key and the key's scopes, the account the
+// key belongs to is set in its metadata.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	authstore "c-z.dev/go-micro/auth/store"
+	"c-z.dev/go-micro/logger"
+	"c-z.dev/go-micro/store"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidKey is returned when the key is malformed, unknown, revoked or expired
+	ErrInvalidKey = errors.New("invalid api key")
+	// ErrInvalidScopes is returned when creating a key with scopes the account doesn't have
+	ErrInvalidScopes = errors.New("scopes must be a subset of the account's scopes")
+	// ErrNotFound is returned when revoking an unknown key
+	ErrNotFound = errors.New("api key not found")
+
+	// MetadataKey is the account metadata the id of the key used is set in
+	MetadataKey = "Api-Key"
+	// AccountKey is the metadata of the key's token the account the key belongs to is set in
+	AccountKey = "Api-Key-Account"
+)
+
+const (
+	keyPrefix   = "key/"
+	usagePrefix = "usage/"
+)
+
+// Key is an api key, the secret part of the key is only returned when it's created
+type Key struct {
+	// ID of the key
+	ID string `json:"id"`
+	// Name describing what the key is used for
+	Name string `json:"name"`
+	// Account the key belongs to
+	Account string `json:"account"`
+	// Issuer of the account
+	Issuer string `json:"issuer"`
+	// Scopes the key has
+	Scopes []string `json:"scopes"`
+	// Created is when the key was created
+	Created time.Time `json:"created"`
+	// Expiry of the key, zero if the key doesn't expire
+	Expiry time.Time `json:"expiry,omitempty"`
+	// LastUsed is when a request was last made with the key
+	LastUsed time.Time `json:"last_used,omitempty"`
+	// Requests is the number of requests made with the key
+	Requests int64 `json:"requests"`
+}
+
+// Keys manages api keys
+type Keys interface {
+	Init(...Option) error
+	Options() Options
+	// Create a key for the account, the value passed by clients is returned with it
+	Create(acc *auth.Account, opts ...CreateOption) (*Key, string, error)
+	// List the keys of an account, all keys are listed if the account is blank
+	List(account string) ([]*Key, error)
+	// Revoke a key
+	Revoke(id string) error
+	// Verify a key and record its use, the account is limited to the key's scopes
+	Verify(key string) (*auth.Account, error)
+	// Token verifies a key and returns a token issued by the auth for the key
+	Token(key string) (*auth.Token, error)
+	String() string
+}
+
+// record is the stored form of a key
+type record struct {
+	Key
+	// Hash of the secret part of the key
+	Hash string `json:"hash"`
+	// Type of the account
+	Type string `json:"type"`
+	// Metadata of the account
+	Metadata map[string]string `json:"metadata"`
+	// Generated is the digest of the account generated for the key in the
+	// auth, it's generated again when the account the key belongs to changes
+	Generated string `json:"generated"`
+}
+
+// usage of a key is stored separately so the key isn't rewritten on each request
+type usage struct {
+	LastUsed time.Time `json:"last_used"`
+	Requests int64     `json:"requests"`
+}
+
+// issued is a token issued for a key and the account it was issued to
+type issued struct {
+	token   *auth.Token
+	account *auth.Account
+}
+
+type keys struct {
+	sync.RWMutex
+	options Options
+
+	// tokens issued for keys, reused until half their lifetime is left
+	// or the key's account changes
+	tmtx   sync.Mutex
+	tokens map[string]*issued
+
+	// usage recorded since it was last written to the store
+	umtx    sync.Mutex
+	usage   map[string]*usage
+	flushed time.Time
+	// flushes are serialised as the counters are read and written back
+	fmtx sync.Mutex
+}
+
+// NewKeys returns api keys held in a store
+func NewKeys(opts ...Option) Keys {
+	k := &keys{
+		tokens:  make(map[string]*issued),
+		usage:   make(map[string]*usage),
+		flushed: time.Now(),
+	}
+	k.Init(opts...)
+	return k
+}
+
+func (k *keys) Init(opts ...Option) error {
+	k.Lock()
+	defer k.Unlock()
+
+	for _, o := range opts {
+		o(&k.options)
+	}
+	if k.options.Store == nil {
+		k.options.Store = store.DefaultStore
+	}
+	if k.options.Auth == nil {
+		k.options.Auth = auth.DefaultAuth
+	}
+	if k.options.TokenExpiry == 0 {
+		k.options.TokenExpiry = DefaultTokenExpiry
+	}
+	if k.options.FlushInterval == 0 {
+		k.options.FlushInterval = DefaultFlushInterval
+	}
+
+	k.tmtx.Lock()
+	k.tokens = make(map[string]*issued)
+	k.tmtx.Unlock()
+	return nil
+}
+
+func (k *keys) Options() Options {
+	k.RLock()
+	defer k.RUnlock()
+	return k.options
+}
+
+// key returns the store key in the namespace
+func (k *keys) key(prefix, id string) string {
+	return "apikey/" + k.options.Namespace + "/" + prefix + id
+}
+
+func (k *keys) Create(acc *auth.Account, opts ...CreateOption) (*Key, string, error) {
+	if acc == nil || len(acc.ID) == 0 {
+		return nil, "", errors.New("api keys require an account")
+	}
+
+	options := NewCreateOptions(opts...)
+	scopes := acc.Scopes
+	if options.Scopes != nil {
+		for _, s := range options.Scopes {
+			if !include(acc.Scopes, s) {
+				return nil, "", ErrInvalidScopes
+			}
+		}
+		scopes = options.Scopes
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	enc := base64.RawURLEncoding.EncodeToString(secret)
+
+	rec := &record{
+		Key: Key{
+			ID:      uuid.New().String(),
+			Name:    options.Name,
+			Account: acc.ID,
+			Issuer:  acc.Issuer,
+			Scopes:  scopes,
+			Created: time.Now(),
+		},
+		Hash:     hash(enc),
+		Type:     acc.Type,
+		Metadata: acc.Metadata,
+	}
+	if options.Expiry > 0 {
+		rec.Expiry = rec.Created.Add(options.Expiry)
+	}
+	rec.Generated = rec.digest()
+
+	k.RLock()
+	defer k.RUnlock()
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := k.options.Store.Write(&store.Record{Key: k.key(keyPrefix, rec.ID), Value: b}); err != nil {
+		return nil, "", err
+	}
+	if err := k.generate(rec, enc); err != nil {
+		k.options.Store.Delete(k.key(keyPrefix, rec.ID))
+		return nil, "", err
+	}
+
+	key := rec.Key
+	return &key, rec.ID + "." + enc, nil
+}
+
+func (k *keys) List(account string) ([]*Key, error) {
+	k.RLock()
+	defer k.RUnlock()
+
+	// list the usage recorded by this instance
+	if err := k.flush(); err != nil {
+		return nil, err
+	}
+
+	recs, err := k.options.Store.Read(k.key(keyPrefix, ""), store.ReadPrefix())
+	if err == store.ErrNotFound {
+		return []*Key{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(recs))
+	for _, r := range recs {
+		var rec *record
+		if err := json.Unmarshal(r.Value, &rec); err != nil {
+			return nil, err
+		}
+		if len(account) > 0 && rec.Account != account {
+			continue
+		}
+		u, err := k.readUsage(rec.ID)
+		if err != nil {
+			return nil, err
+		}
+		rec.LastUsed = u.LastUsed
+		rec.Requests = u.Requests
+		key := rec.Key
+		keys = append(keys, &key)
+	}
+	return keys, nil
+}
+
+func (k *keys) Revoke(id string) error {
+	k.RLock()
+	defer k.RUnlock()
+
+	k.tmtx.Lock()
+	delete(k.tokens, id)
+	k.tmtx.Unlock()
+
+	// usage being flushed isn't written back once the key is deleted
+	k.fmtx.Lock()
+	defer k.fmtx.Unlock()
+	k.umtx.Lock()
+	delete(k.usage, id)
+	k.umtx.Unlock()
+
+	recs, err := k.options.Store.Read(k.key(keyPrefix, id))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	if err := k.options.Store.Delete(k.key(keyPrefix, id)); err != nil && err != store.ErrNotFound {
+		return err
+	}
+	if err := k.options.Store.Delete(k.key(usagePrefix, id)); err != nil && err != store.ErrNotFound {
+		return err
+	}
+
+	// the tokens issued for the key can no longer be refreshed
+	if r, ok := k.options.Auth.(authstore.Revoker); ok {
+		return r.RevokeAccount(id)
+	}
+	return nil
+}
+
+func (k *keys) Verify(key string) (*auth.Account, error) {
+	rec, err := k.verify(key)
+	if err != nil {
+		return nil, err
+	}
+	return rec.account(), nil
+}
+
+func (k *keys) Token(key string) (*auth.Token, error) {
+	rec, err := k.verify(key)
+	if err != nil {
+		return nil, err
+	}
+	acc := rec.account()
+
+	k.tmtx.Lock()
+	t, ok := k.tokens[rec.ID]
+	k.tmtx.Unlock()
+	if ok && time.Until(t.token.Expiry) > k.options.TokenExpiry/2 && reflect.DeepEqual(t.account, acc) {
+		return t.token, nil
+	}
+
+	k.RLock()
+	defer k.RUnlock()
+
+	// the key's account was generated with the secret of the key, so the auth
+	// verifies the key itself. It's generated again if the account changed.
+	secret := strings.SplitN(key, ".", 2)[1]
+	if digest := rec.digest(); digest != rec.Generated {
+		if err := k.generate(rec, secret); err != nil {
+			return nil, err
+		}
+		if err := k.generated(rec.ID, digest); err != nil {
+			return nil, err
+		}
+	}
+	tok, err := k.options.Auth.Token(auth.WithCredentials(rec.ID, secret), auth.WithExpiry(k.options.TokenExpiry))
+	if err != nil {
+		return nil, err
+	}
+
+	k.tmtx.Lock()
+	k.tokens[rec.ID] = &issued{token: tok, account: acc}
+	k.tmtx.Unlock()
+	return tok, nil
+}
+
+func (k *keys) String() string {
+	return "store"
+}
+
+// generate the account of the key in the auth, the account has the id and secret
+// of the key and the account the key belongs to is set in its metadata
+func (k *keys) generate(rec *record, secret string) error {
+	acc := rec.account()
+	md := make(map[string]string, len(acc.Metadata)+1)
+	for n, v := range acc.Metadata {
+		md[n] = v
+	}
+	md[AccountKey] = acc.ID
+
+	_, err := k.options.Auth.Generate(rec.ID,
+		auth.WithSecret(secret),
+		auth.WithType(acc.Type),
+		auth.WithScopes(acc.Scopes...),
+		auth.WithMetadata(md),
+		auth.WithProvider("apikey"),
+	)
+	return err
+}
+
+// generated records the digest of the account generated for the key
+func (k *keys) generated(id, digest string) error {
+	recs, err := k.options.Store.Read(k.key(keyPrefix, id))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return ErrInvalidKey
+	} else if err != nil {
+		return err
+	}
+
+	var rec *record
+	if err := json.Unmarshal(recs[0].Value, &rec); err != nil {
+		return err
+	}
+	rec.Generated = digest
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return k.options.Store.Write(&store.Record{Key: k.key(keyPrefix, id), Value: b})
+}
+
+// verify checks the key and its account are valid and records its use
+func (k *keys) verify(key string) (*record, error) {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	k.RLock()
+	defer k.RUnlock()
+
+	recs, err := k.options.Store.Read(k.key(keyPrefix, parts[0]))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, ErrInvalidKey
+	} else if err != nil {
+		return nil, err
+	}
+
+	var rec *record
+	if err := json.Unmarshal(recs[0].Value, &rec); err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(rec.Hash), []byte(hash(parts[1]))) != 1 {
+		return nil, ErrInvalidKey
+	}
+	if !rec.Expiry.IsZero() && rec.Expiry.Before(time.Now()) {
+		return nil, ErrInvalidKey
+	}
+
+	// the account may have changed since the key was created, keys of
+	// deleted accounts are invalid and scopes the account lost are removed
+	if r, ok := k.options.Auth.(authstore.Reader); ok {
+		acc, err := r.ReadAccount(rec.Account)
+		if err == store.ErrNotFound {
+			return nil, ErrInvalidKey
+		} else if err != nil {
+			return nil, err
+		}
+		var scopes []string
+		for _, s := range rec.Scopes {
+			if include(acc.Scopes, s) {
+				scopes = append(scopes, s)
+			}
+		}
+		rec.Scopes = scopes
+		rec.Type = acc.Type
+		rec.Metadata = acc.Metadata
+	}
+
+	k.record(rec.ID)
+	return rec, nil
+}
+
+// record the use of a key, the usage is written to the store in batches
+func (k *keys) record(id string) {
+	k.umtx.Lock()
+	defer k.umtx.Unlock()
+
+	u, ok := k.usage[id]
+	if !ok {
+		u = &usage{}
+		k.usage[id] = u
+	}
+	u.LastUsed = time.Now()
+	u.Requests++
+
+	if time.Since(k.flushed) < k.options.FlushInterval {
+		return
+	}
+	k.flushed = time.Now()
+	go func() {
+		k.RLock()
+		defer k.RUnlock()
+		if err := k.flush(); err != nil {
+			logger.Errorf("Error writing api key usage: %v", err)
+		}
+	}()
+}
+
+// flush writes the recorded usage to the store, usage which can't be written
+// is kept to be written with the next flush
+func (k *keys) flush() error {
+	k.fmtx.Lock()
+	defer k.fmtx.Unlock()
+
+	k.umtx.Lock()
+	pending := k.usage
+	k.usage = make(map[string]*usage)
+	k.umtx.Unlock()
+
+	var gerr error
+	for id, p := range pending {
+		if err := k.writeUsage(id, p); err != nil {
+			gerr = err
+			k.umtx.Lock()
+			if u, ok := k.usage[id]; ok {
+				u.Requests += p.Requests
+				if p.LastUsed.After(u.LastUsed) {
+					u.LastUsed = p.LastUsed
+				}
+			} else {
+				k.usage[id] = p
+			}
+			k.umtx.Unlock()
+		}
+	}
+	return gerr
+}
+
+// writeUsage adds the usage to the stored counters, they're read and written
+// back so usage flushed by other replicas at the same time may not be counted
+func (k *keys) writeUsage(id string, p *usage) error {
+	u, err := k.readUsage(id)
+	if err != nil {
+		return err
+	}
+	u.Requests += p.Requests
+	if p.LastUsed.After(u.LastUsed) {
+		u.LastUsed = p.LastUsed
+	}
+	b, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return k.options.Store.Write(&store.Record{Key: k.key(usagePrefix, id), Value: b})
+}
+
+func (k *keys) readUsage(id string) (*usage, error) {
+	u := &usage{}
+	recs, err := k.options.Store.Read(k.key(usagePrefix, id))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return u, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(recs[0].Value, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// account returns the account of the key limited to its scopes
+func (r *record) account() *auth.Account {
+	md := make(map[string]string, len(r.Metadata)+1)
+	for k, v := range r.Metadata {
+		md[k] = v
+	}
+	md[MetadataKey] = r.ID
+
+	return &auth.Account{
+		ID:       r.Account,
+		Type:     r.Type,
+		Issuer:   r.Issuer,
+		Metadata: md,
+		Scopes:   r.Scopes,
+	}
+}
+
+// digest of the account of the key, it changes when the account the key belongs to does
+func (r *record) digest() string {
+	b, _ := json.Marshal(r.account())
+	return hash(string(b))
+}
+
+func hash(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+func include(slice []string, val string) bool {
+	for _, s := range slice {
+		if s == val {
+			return true
+		}
+	}
+	return false
+}
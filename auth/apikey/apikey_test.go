@@ -0,0 +1,173 @@
+package apikey
+
+import (
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	authstore "c-z.dev/go-micro/auth/store"
+	"c-z.dev/go-micro/store/memory"
+)
+
+func TestKeys(t *testing.T) {
+	s := memory.NewStore()
+	a := authstore.NewAuth(auth.Store(s), auth.Namespace("micro"))
+	k := NewKeys(WithStore(s), WithNamespace("micro"), WithAuth(a))
+
+	acc, err := a.Generate("john",
+		auth.WithScopes("read", "write"),
+		auth.WithMetadata(map[string]string{"team": "payments"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := k.Create(acc, WithScopes("admin")); err != ErrInvalidScopes {
+		t.Fatalf("Expected invalid scopes error but got %v", err)
+	}
+
+	key, secret, err := k.Create(acc, WithName("reporting"), WithScopes("read"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Account != "john" || key.Name != "reporting" {
+		t.Fatalf("Unexpected key %+v", key)
+	}
+
+	// the account is limited to the key's scopes
+	va, err := k.Verify(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if va.ID != "john" || len(va.Scopes) != 1 || va.Scopes[0] != "read" {
+		t.Fatalf("Unexpected account %+v", va)
+	}
+	if va.Metadata[MetadataKey] != key.ID || va.Metadata["team"] != "payments" {
+		t.Fatalf("Unexpected account metadata %v", va.Metadata)
+	}
+
+	for _, bad := range []string{"", "nope", key.ID + ".wrong", "unknown." + secret} {
+		if _, err := k.Verify(bad); err != ErrInvalidKey {
+			t.Fatalf("Expected invalid key error for %q but got %v", bad, err)
+		}
+	}
+
+	// the token is issued by the auth for the key's account
+	tok, err := k.Token(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ta, err := a.Inspect(tok.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ta.ID != key.ID || len(ta.Scopes) != 1 || ta.Scopes[0] != "read" || ta.Metadata[AccountKey] != "john" {
+		t.Fatalf("Unexpected token account %+v", ta)
+	}
+	if tok2, err := k.Token(secret); err != nil || tok2.AccessToken != tok.AccessToken {
+		t.Fatalf("Expected the token to be reused, got %v %v", tok2, err)
+	}
+
+	// usage is tracked for each request
+	keys, err := k.List("john")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("Expected 1 key but got %v", len(keys))
+	}
+	if keys[0].Requests != 3 || keys[0].LastUsed.IsZero() {
+		t.Fatalf("Expected 3 requests and last used to be set, got %+v", keys[0])
+	}
+	if keys, _ := k.List("jane"); len(keys) != 0 {
+		t.Fatalf("Expected no keys for jane but got %v", len(keys))
+	}
+
+	// tokens issued by other replicas don't invalidate each other
+	if _, err := NewKeys(WithStore(s), WithNamespace("micro"), WithAuth(a)).Token(secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Token(auth.WithToken(tok.RefreshToken)); err != nil {
+		t.Fatalf("Expected the token to be refreshed, got %v", err)
+	}
+
+	// changes to the account apply to its keys
+	if _, err := a.Generate("john", auth.WithScopes("write"), auth.WithMetadata(map[string]string{"team": "billing"})); err != nil {
+		t.Fatal(err)
+	}
+	if va, err := k.Verify(secret); err != nil || len(va.Scopes) != 0 || va.Metadata["team"] != "billing" {
+		t.Fatalf("Expected the account's changes to apply, got %+v %v", va, err)
+	}
+	tok2, err := k.Token(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ta, err := a.Inspect(tok2.AccessToken); err != nil || len(ta.Scopes) != 0 || ta.Metadata["team"] != "billing" {
+		t.Fatalf("Expected a token for the changed account, got %+v %v", ta, err)
+	}
+	if err := a.(authstore.Revoker).RevokeAccount("john"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Verify(secret); err != ErrInvalidKey {
+		t.Fatalf("Expected the key of a deleted account to be invalid but got %v", err)
+	}
+
+	if err := k.Revoke(key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Verify(secret); err != ErrInvalidKey {
+		t.Fatalf("Expected revoked key to be invalid but got %v", err)
+	}
+	if _, err := k.Token(secret); err != ErrInvalidKey {
+		t.Fatalf("Expected revoked key to be invalid but got %v", err)
+	}
+	if _, err := a.Token(auth.WithToken(tok.RefreshToken)); err == nil {
+		t.Fatal("Expected the key's token not to be refreshed once revoked")
+	}
+	if err := k.Revoke(key.ID); err != ErrNotFound {
+		t.Fatalf("Expected not found error but got %v", err)
+	}
+}
+
+func TestKeyExpiry(t *testing.T) {
+	k := NewKeys(WithStore(memory.NewStore()))
+
+	_, secret, err := k.Create(&auth.Account{ID: "john"}, WithExpiry(time.Millisecond*10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Verify(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond * 20)
+
+	if _, err := k.Verify(secret); err != ErrInvalidKey {
+		t.Fatalf("Expected expired key to be invalid but got %v", err)
+	}
+}
+
+func TestUsageFlush(t *testing.T) {
+	k := NewKeys(WithStore(memory.NewStore()), WithFlushInterval(time.Millisecond*10))
+
+	key, secret, err := k.Create(&auth.Account{ID: "john"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := k.Verify(secret); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	time.Sleep(time.Millisecond * 10)
+
+	// the usage is written in the background
+	u, err := k.(*keys).readUsage(key.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Requests == 0 || u.LastUsed.IsZero() {
+		t.Fatalf("Expected the usage to be written, got %+v", u)
+	}
+}
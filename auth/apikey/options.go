@@ -0,0 +1,109 @@
+package apikey
+
+import (
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/store"
+)
+
+var (
+	// DefaultTokenExpiry is how long the tokens issued for api keys are valid for
+	DefaultTokenExpiry = time.Minute * 5
+	// DefaultFlushInterval is how often the usage of the keys is written to the store
+	DefaultFlushInterval = time.Second * 10
+)
+
+type Options struct {
+	// Namespace the keys belong to
+	Namespace string
+	// Store the keys and their usage are kept in
+	Store store.Store
+	// Auth issues the tokens requests made with a key are forwarded with
+	Auth auth.Auth
+	// TokenExpiry is how long the issued tokens are valid for
+	TokenExpiry time.Duration
+	// FlushInterval is how often the usage of the keys is written to the store
+	FlushInterval time.Duration
+}
+
+type Option func(o *Options)
+
+// WithNamespace sets the namespace the keys belong to
+func WithNamespace(ns string) Option {
+	return func(o *Options) {
+		o.Namespace = ns
+	}
+}
+
+// WithStore sets the store the keys are kept in, it defaults to store.DefaultStore
+func WithStore(s store.Store) Option {
+	return func(o *Options) {
+		o.Store = s
+	}
+}
+
+// WithAuth sets the auth the tokens are issued by, it defaults to auth.DefaultAuth. It
+// should be the auth of the gateway so the services called can inspect the tokens
+func WithAuth(a auth.Auth) Option {
+	return func(o *Options) {
+		o.Auth = a
+	}
+}
+
+// WithTokenExpiry sets how long the issued tokens are valid for
+func WithTokenExpiry(d time.Duration) Option {
+	return func(o *Options) {
+		o.TokenExpiry = d
+	}
+}
+
+// WithFlushInterval sets how often the usage of the keys is written to the store,
+// the usage is also written when the keys are listed
+func WithFlushInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.FlushInterval = d
+	}
+}
+
+type CreateOptions struct {
+	// Name describing what the key is used for
+	Name string
+	// Scopes the key has, a subset of the account's scopes
+	Scopes []string
+	// Expiry of the key, zero means the key doesn't expire
+	Expiry time.Duration
+}
+
+type CreateOption func(o *CreateOptions)
+
+// WithName sets the name of the key
+func WithName(n string) CreateOption {
+	return func(o *CreateOptions) {
+		o.Name = n
+	}
+}
+
+// WithScopes limits the key to a subset of the account's scopes, by default
+// the key has all of them
+func WithScopes(s ...string) CreateOption {
+	return func(o *CreateOptions) {
+		o.Scopes = s
+	}
+}
+
+// WithExpiry sets how long the key is valid for
+func WithExpiry(d time.Duration) CreateOption {
+	return func(o *CreateOptions) {
+		o.Expiry = d
+	}
+}
+
+// NewCreateOptions from a slice of options
+func NewCreateOptions(opts ...CreateOption) CreateOptions {
+	var options CreateOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
@@ -0,0 +1,251 @@
+// Package audit records the authentication and authorization decisions made by auth
+package audit
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/logger"
+	"github.com/google/uuid"
+)
+
+// Decision is the outcome of an auth check
+type Decision string
+
+const (
+	// DecisionGranted is recorded when the request was allowed
+	DecisionGranted = Decision("granted")
+	// DecisionDenied is recorded when the request was rejected
+	DecisionDenied = Decision("denied")
+)
+
+var (
+	// ErrBufferFull is returned when an event is dropped as the sinks are behind
+	ErrBufferFull = errors.New("audit buffer is full")
+)
+
+// Event is a structured record of an auth decision
+type Event struct {
+	// ID of the event
+	ID string `json:"id"`
+	// Timestamp the decision was made
+	Timestamp time.Time `json:"timestamp"`
+	// Account is the ID of the account making the request, blank for public requests
+	Account string `json:"account,omitempty"`
+	// Issuer of the account
+	Issuer string `json:"issuer,omitempty"`
+	// Scopes of the account
+	Scopes []string `json:"scopes,omitempty"`
+	// Resource being accessed
+	Resource *auth.Resource `json:"resource,omitempty"`
+	// Rule is the ID of the rule which decided the request, blank if no rule matched
+	Rule string `json:"rule,omitempty"`
+	// Decision made
+	Decision Decision `json:"decision"`
+	// Reason the request was denied
+	Reason string `json:"reason,omitempty"`
+	// Caller is the name of the service which made the request
+	Caller string `json:"caller,omitempty"`
+}
+
+// Sink is where audit events are written to
+type Sink interface {
+	Write(*Event) error
+	String() string
+}
+
+// Auditor records auth decisions to its sinks. Denials are always recorded,
+// granted requests are sampled.
+type Auditor interface {
+	Init(...Option) error
+	Options() Options
+	// Record queues the event to be written to the sinks, the event
+	// is dropped and ErrBufferFull returned if the buffer is full
+	Record(*Event) error
+	// Flush waits for the queued events to be written
+	Flush() error
+	String() string
+}
+
+// NewAuditor returns an auditor writing to the sinks provided. Events are
+// written in the background so the sinks don't delay the requests audited.
+func NewAuditor(opts ...Option) Auditor {
+	options := Options{
+		SampleRate: DefaultSampleRate,
+		BufferSize: DefaultBufferSize,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	a := &auditor{
+		options: options,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	a.resize(options.BufferSize)
+	return a
+}
+
+// queued is an event waiting to be written, or a flush
+type queued struct {
+	event *Event
+	done  chan bool
+}
+
+type auditor struct {
+	sync.RWMutex
+	options Options
+
+	// emtx guards the events, which are replaced when the buffer is resized
+	emtx   sync.RWMutex
+	events chan *queued
+	// closed once the events have been written
+	done chan bool
+	// number of events dropped since the last was written
+	dropped int64
+
+	rmtx sync.Mutex
+	rand *rand.Rand
+}
+
+func (a *auditor) Init(opts ...Option) error {
+	a.Lock()
+	size := a.options.BufferSize
+	for _, o := range opts {
+		o(&a.options)
+	}
+	resized := a.options.BufferSize != size
+	size = a.options.BufferSize
+	a.Unlock()
+
+	if resized {
+		a.resize(size)
+	}
+	return nil
+}
+
+func (a *auditor) Options() Options {
+	a.RLock()
+	defer a.RUnlock()
+	return a.options
+}
+
+// resize replaces the buffer of events, the events already queued are
+// written before those queued in the new buffer
+func (a *auditor) resize(size int) {
+	a.emtx.Lock()
+	defer a.emtx.Unlock()
+
+	if a.events != nil {
+		close(a.events)
+	}
+
+	events := make(chan *queued, size)
+	done := make(chan bool)
+	go a.run(events, a.done, done)
+	a.events, a.done = events, done
+}
+
+// sample decides if a granted request should be recorded
+func (a *auditor) sample() bool {
+	rate := a.Options().SampleRate
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	a.rmtx.Lock()
+	defer a.rmtx.Unlock()
+	return a.rand.Float64() < rate
+}
+
+func (a *auditor) Record(e *Event) error {
+	if e.Decision == DecisionGranted && !a.sample() {
+		return nil
+	}
+	if len(e.ID) == 0 {
+		e.ID = uuid.New().String()
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+
+	a.emtx.RLock()
+	defer a.emtx.RUnlock()
+
+	select {
+	case a.events <- &queued{event: e}:
+		return nil
+	default:
+		atomic.AddInt64(&a.dropped, 1)
+		return ErrBufferFull
+	}
+}
+
+func (a *auditor) Flush() error {
+	done := make(chan bool)
+	a.emtx.RLock()
+	a.events <- &queued{done: done}
+	a.emtx.RUnlock()
+	<-done
+	return nil
+}
+
+// run writes the queued events to the sinks once the events of the
+// previous buffer have been written
+func (a *auditor) run(events chan *queued, prev, done chan bool) {
+	defer close(done)
+
+	if prev != nil {
+		<-prev
+	}
+
+	for q := range events {
+		if q.done != nil {
+			close(q.done)
+			continue
+		}
+
+		if n := atomic.SwapInt64(&a.dropped, 0); n > 0 {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("Dropped %d audit events, the audit buffer was full", n)
+			}
+		}
+
+		// write to all the sinks, a failing sink doesn't prevent the others being written to
+		for _, s := range a.Options().Sinks {
+			if err := s.Write(q.event); err != nil {
+				if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+					logger.Errorf("Error writing audit event %v to %v: %v", q.event.ID, s, err)
+				}
+			}
+		}
+	}
+}
+
+func (a *auditor) String() string {
+	return "audit"
+}
+
+type auditorKey struct{}
+
+// FromContext returns the auditor set in the context
+func FromContext(ctx context.Context) (Auditor, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	a, ok := ctx.Value(auditorKey{}).(Auditor)
+	return a, ok
+}
+
+// NewContext sets the auditor in the context. It's used by auth.VerifyContext to pass
+// the auditor to rules.Verify.
+func NewContext(ctx context.Context, a Auditor) context.Context {
+	return context.WithValue(ctx, auditorKey{}, a)
+}
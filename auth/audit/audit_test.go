@@ -0,0 +1,194 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/broker/memory"
+	"c-z.dev/go-micro/store"
+	mstore "c-z.dev/go-micro/store/memory"
+)
+
+type testSink struct {
+	events []*Event
+}
+
+func (t *testSink) Write(e *Event) error {
+	t.events = append(t.events, e)
+	return nil
+}
+
+func (t *testSink) String() string {
+	return "test"
+}
+
+func TestAuditorSampling(t *testing.T) {
+	res := &auth.Resource{Type: "service", Name: "go.micro.service.foo", Endpoint: "Foo.Bar"}
+
+	tt := []struct {
+		Name     string
+		Rate     float64
+		Decision Decision
+		Count    int
+	}{
+		{Name: "DeniedAlwaysRecorded", Rate: 0, Decision: DecisionDenied, Count: 10},
+		{Name: "GrantedNotSampled", Rate: 0, Decision: DecisionGranted, Count: 0},
+		{Name: "GrantedAllSampled", Rate: 1, Decision: DecisionGranted, Count: 10},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			s := &testSink{}
+			a := NewAuditor(WithSink(s), SampleRate(tc.Rate))
+			for i := 0; i < 10; i++ {
+				if err := a.Record(&Event{Resource: res, Decision: tc.Decision}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			a.Flush()
+			if len(s.events) != tc.Count {
+				t.Fatalf("Expected %v events but got %v", tc.Count, len(s.events))
+			}
+			for _, e := range s.events {
+				if len(e.ID) == 0 || e.Timestamp.IsZero() {
+					t.Fatalf("Expected the event ID and timestamp to be set")
+				}
+			}
+		})
+	}
+}
+
+// blockingSink blocks writes until released
+type blockingSink struct {
+	testSink
+	release chan bool
+}
+
+func (b *blockingSink) Write(e *Event) error {
+	<-b.release
+	return b.testSink.Write(e)
+}
+
+func TestAuditorBuffer(t *testing.T) {
+	s := &blockingSink{release: make(chan bool)}
+	a := NewAuditor(WithSink(s), BufferSize(1))
+
+	// the first event is being written, the second is queued
+	e := &Event{Decision: DecisionDenied}
+	for i := 0; i < 2; i++ {
+		if err := a.Record(e); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	// the record doesn't wait for the sink
+	if err := a.Record(e); err != ErrBufferFull {
+		t.Fatalf("Expected buffer full error but got %v", err)
+	}
+
+	close(s.release)
+	a.Flush()
+	if len(s.events) != 2 {
+		t.Fatalf("Expected 2 events but got %v", len(s.events))
+	}
+}
+
+func TestAuditorInit(t *testing.T) {
+	s := &testSink{}
+	a := NewAuditor(SampleRate(1))
+
+	// the options set by NewAuditor are kept
+	if err := a.Init(WithSink(s)); err != nil {
+		t.Fatal(err)
+	}
+	if r := a.Options().SampleRate; r != 1 {
+		t.Fatalf("Expected sample rate 1 but got %v", r)
+	}
+
+	// events queued before the buffer is resized are still written
+	e := &Event{Decision: DecisionGranted}
+	if err := a.Record(e); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Init(BufferSize(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Record(e); err != nil {
+		t.Fatal(err)
+	}
+
+	a.Flush()
+	if len(s.events) != 2 {
+		t.Fatalf("Expected 2 events but got %v", len(s.events))
+	}
+}
+
+func TestSinks(t *testing.T) {
+	e := &Event{
+		Account:  "john",
+		Resource: &auth.Resource{Type: "service", Name: "go.micro.service.foo", Endpoint: "Foo.Bar"},
+		Rule:     "admins",
+		Decision: DecisionDenied,
+		Caller:   "go.micro.service.bar",
+	}
+
+	t.Run("Store", func(t *testing.T) {
+		s := mstore.NewStore()
+		a := NewAuditor(WithSink(NewStoreSink(s, store.WriteTo("micro", "audit"))))
+		if err := a.Record(e); err != nil {
+			t.Fatal(err)
+		}
+		a.Flush()
+
+		recs, err := s.Read("", store.ReadFrom("micro", "audit"), store.ReadPrefix())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(recs) != 1 {
+			t.Fatalf("Expected 1 record but got %v", len(recs))
+		}
+		var got Event
+		if err := json.Unmarshal(recs[0].Value, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != e.ID || got.Rule != "admins" || got.Caller != e.Caller {
+			t.Fatalf("Expected %+v but got %+v", e, got)
+		}
+	})
+
+	t.Run("Broker", func(t *testing.T) {
+		b := memory.NewBroker()
+		if err := b.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		defer b.Disconnect()
+
+		msgs := make(chan *broker.Message, 1)
+		sub, err := b.Subscribe("audit", func(p broker.Event) error {
+			msgs <- p.Message()
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sub.Unsubscribe()
+
+		a := NewAuditor(WithSink(NewBrokerSink(b, "audit")))
+		if err := a.Record(e); err != nil {
+			t.Fatal(err)
+		}
+
+		msg := <-msgs
+		var got Event
+		if err := json.Unmarshal(msg.Body, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Decision != DecisionDenied || got.Account != "john" {
+			t.Fatalf("Expected %+v but got %+v", e, got)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"context"
+
+	"c-z.dev/go-micro/auth"
+)
+
+var (
+	// DefaultSampleRate is the fraction of granted requests which are recorded
+	DefaultSampleRate = 0.1
+	// DefaultBufferSize is the number of events queued to be written to the sinks
+	DefaultBufferSize = 1024
+)
+
+type Options struct {
+	// Sinks the events are written to
+	Sinks []Sink
+	// SampleRate is the fraction of granted requests recorded, between 0 and 1.
+	// Denied requests are always recorded.
+	SampleRate float64
+	// BufferSize is the number of events queued to be written to the sinks,
+	// events recorded when the buffer is full are dropped
+	BufferSize int
+}
+
+type Option func(o *Options)
+
+// WithSink adds a sink the events are written to
+func WithSink(s Sink) Option {
+	return func(o *Options) {
+		o.Sinks = append(o.Sinks, s)
+	}
+}
+
+// SampleRate sets the fraction of granted requests which are recorded
+func SampleRate(r float64) Option {
+	return func(o *Options) {
+		o.SampleRate = r
+	}
+}
+
+// BufferSize sets the number of events queued to be written to the sinks
+func BufferSize(n int) Option {
+	return func(o *Options) {
+		o.BufferSize = n
+	}
+}
+
+// WithAuditor sets the auditor used to record the decisions of auth
+func WithAuditor(a Auditor) auth.Option {
+	return func(o *auth.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = NewContext(o.Context, a)
+	}
+}
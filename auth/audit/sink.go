@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/debug/log"
+	"c-z.dev/go-micro/store"
+)
+
+// NewLogSink returns a sink which writes events to a debug log
+func NewLogSink(l log.Log) Sink {
+	return &logSink{l}
+}
+
+type logSink struct {
+	log log.Log
+}
+
+func (l *logSink) Write(e *Event) error {
+	return l.log.Write(log.Record{
+		Timestamp: e.Timestamp,
+		Metadata: map[string]string{
+			"type":     "audit",
+			"decision": string(e.Decision),
+		},
+		Message: e,
+	})
+}
+
+func (l *logSink) String() string {
+	return "log"
+}
+
+// NewStoreSink returns a sink which writes events to a store, the write options can be used
+// to set the table and how long events are retained for. Events are keyed by their timestamp
+// so they can be listed in order.
+func NewStoreSink(s store.Store, opts ...store.WriteOption) Sink {
+	return &storeSink{s, opts}
+}
+
+type storeSink struct {
+	store store.Store
+	opts  []store.WriteOption
+}
+
+func (s *storeSink) Write(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.store.Write(&store.Record{
+		Key:   fmt.Sprintf("%020d/%s", e.Timestamp.UnixNano(), e.ID),
+		Value: b,
+	}, s.opts...)
+}
+
+func (s *storeSink) String() string {
+	return "store"
+}
+
+// NewBrokerSink returns a sink which publishes events to a broker topic
+func NewBrokerSink(b broker.Broker, topic string) Sink {
+	return &brokerSink{b, topic}
+}
+
+type brokerSink struct {
+	broker broker.Broker
+	topic  string
+}
+
+func (b *brokerSink) Write(e *Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return b.broker.Publish(b.topic, &broker.Message{
+		Header: map[string]string{
+			"Content-Type": "application/json",
+			"Micro-Topic":  b.topic,
+			"Micro-Id":     e.ID,
+		},
+		Body: body,
+	})
+}
+
+func (b *brokerSink) String() string {
+	return "broker"
+}
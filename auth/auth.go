@@ -114,6 +114,43 @@ type Rule struct {
 	// Priority the rule should take when verifying a request, the higher the value the sooner the
 	// rule will be applied
 	Priority int32
+	// Conditions which must all hold for the rule to apply
+	Conditions []*Condition
+}
+
+// Operator compares an attribute of a request with the values of a condition
+type Operator string
+
+const (
+	// OperatorEqual requires the attribute to equal the value
+	OperatorEqual Operator = "=="
+	// OperatorNotEqual requires the attribute to not equal the value
+	OperatorNotEqual Operator = "!="
+	// OperatorIn requires the attribute to equal one of the values
+	OperatorIn Operator = "in"
+	// OperatorNotIn requires the attribute to equal none of the values
+	OperatorNotIn Operator = "not in"
+	// OperatorPrefix requires the attribute to start with one of the values
+	OperatorPrefix Operator = "prefix"
+	// OperatorExists requires the attribute to be set
+	OperatorExists Operator = "exists"
+	// OperatorBetween requires the attribute to be within the two values, inclusive
+	OperatorBetween Operator = "between"
+)
+
+// Condition restricts a rule using attributes of the request. Attributes are
+// account.id, account.type, account.issuer, account.metadata.<key>,
+// metadata.<key> for request metadata, resource.name, resource.type,
+// resource.endpoint, time (RFC 3339), time.clock (15:04) and time.weekday
+// (Mon). Times are UTC. A value starting with $ references another attribute,
+// e.g. account.metadata.tenant == $metadata.Micro-Tenant
+type Condition struct {
+	// Attribute of the request
+	Attribute string
+	// Operator used to compare the attribute with the values
+	Operator Operator
+	// Values the attribute is compared with
+	Values []string
 }
 
 type accountKey struct{}
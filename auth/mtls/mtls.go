@@ -0,0 +1,101 @@
+// Package mtls authenticates services by the SPIFFE ID in their client certificate.
+// Transports verify the certificate and pass the ID in the transport.PeerIdentityHeader
+// which is mapped to an account the auth rules can be applied to.
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/metadata"
+	"c-z.dev/go-micro/transport"
+)
+
+const (
+	// AccountType of accounts authenticated by certificate
+	AccountType = "service"
+)
+
+// Account returns the account for a SPIFFE ID e.g. spiffe://example.org/service/foo.
+// The account is issued by the trust domain and its scopes are the ID itself, so
+// rules can be granted to a service's ID or a prefix e.g. spiffe://example.org/*
+func Account(id string) (*auth.Account, error) {
+	u, err := url.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "spiffe" || len(u.Host) == 0 {
+		return nil, fmt.Errorf("%s is not a SPIFFE ID", id)
+	}
+
+	return &auth.Account{
+		ID:     id,
+		Type:   AccountType,
+		Issuer: u.Host,
+		Scopes: []string{id},
+		Metadata: map[string]string{
+			"provider":     "mtls",
+			"trust_domain": u.Host,
+			"path":         u.Path,
+		},
+	}, nil
+}
+
+// AccountFromContext returns the account of the peer's verified certificate
+func AccountFromContext(ctx context.Context) (*auth.Account, bool) {
+	id, ok := metadata.Get(ctx, transport.PeerIdentityHeader)
+	if !ok || len(id) == 0 {
+		return nil, false
+	}
+	acc, err := Account(id)
+	if err != nil {
+		return nil, false
+	}
+	return acc, true
+}
+
+// ServerConfig returns a tls config which requires client certificates signed
+// by the CA, e.g. for transport.TLSConfig. All arguments are PEM encoded.
+func ServerConfig(ca, cert, key []byte) (*tls.Config, error) {
+	pool, pair, err := load(ca, cert, key)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientConfig returns a tls config which presents the certificate and
+// verifies servers against the CA. All arguments are PEM encoded.
+func ClientConfig(ca, cert, key []byte) (*tls.Config, error) {
+	pool, pair, err := load(ca, cert, key)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func load(ca, cert, key []byte) (*x509.CertPool, tls.Certificate, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, tls.Certificate{}, errors.New("no CA certificates found")
+	}
+	pair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, tls.Certificate{}, err
+	}
+	return pool, pair, nil
+}
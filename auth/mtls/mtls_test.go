@@ -0,0 +1,43 @@
+package mtls
+
+import (
+	"context"
+	"testing"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/rules"
+	"c-z.dev/go-micro/metadata"
+	"c-z.dev/go-micro/transport"
+)
+
+func TestAccount(t *testing.T) {
+	if _, err := Account("https://example.org/foo"); err == nil {
+		t.Fatal("Expected error for non SPIFFE ID")
+	}
+
+	ctx := metadata.NewContext(context.Background(), metadata.Metadata{
+		transport.PeerIdentityHeader: "spiffe://example.org/ns/prod/sa/foo",
+	})
+	acc, ok := AccountFromContext(ctx)
+	if !ok {
+		t.Fatal("Expected account from context")
+	}
+	if acc.Issuer != "example.org" || acc.Type != AccountType {
+		t.Fatalf("Unexpected account %+v", acc)
+	}
+
+	// rules can be granted to the identity
+	res := &auth.Resource{Type: "service", Name: "go.micro.service.bar", Endpoint: "Bar.Baz"}
+	rs := []*auth.Rule{{
+		ID:       "prod",
+		Scope:    "spiffe://example.org/ns/prod/*",
+		Resource: &auth.Resource{Type: "service", Name: "go.micro.service.bar", Endpoint: "*"},
+	}}
+	if err := rules.Verify(rs, acc, res); err != nil {
+		t.Fatalf("Expected access got %v", err)
+	}
+
+	if _, ok := AccountFromContext(context.Background()); ok {
+		t.Fatal("Expected no account without a peer identity")
+	}
+}
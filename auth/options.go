@@ -44,6 +44,8 @@ type Options struct {
 	Client client.Client
 	// Addrs sets the addresses of auth
 	Addrs []string
+	// Context for other opts
+	Context context.Context
 }
 
 type Option func(o *Options)
@@ -0,0 +1,82 @@
+package policy
+
+import (
+	"encoding/json"
+	"sort"
+
+	"c-z.dev/go-micro/auth"
+)
+
+// Changes needed to get from the live rules to the declared ones
+type Changes struct {
+	// Create are declared rules which don't exist
+	Create []*auth.Rule
+	// Update are declared rules which differ from the live ones
+	Update []*auth.Rule
+	// Delete are live rules which are no longer declared
+	Delete []*auth.Rule
+}
+
+// Empty returns true if there are no changes
+func (c *Changes) Empty() bool {
+	return len(c.Create) == 0 && len(c.Update) == 0 && len(c.Delete) == 0
+}
+
+// Diff the live rules with the declared ones, rules are matched by id
+func Diff(live, declared []*auth.Rule) *Changes {
+	current := make(map[string]*auth.Rule, len(live))
+	for _, r := range live {
+		current[r.ID] = r
+	}
+
+	changes := &Changes{}
+	seen := make(map[string]bool, len(declared))
+	for _, r := range declared {
+		seen[r.ID] = true
+		c, ok := current[r.ID]
+		if !ok {
+			changes.Create = append(changes.Create, r)
+		} else if !equal(c, r) {
+			changes.Update = append(changes.Update, r)
+		}
+	}
+	for _, r := range live {
+		if !seen[r.ID] {
+			changes.Delete = append(changes.Delete, r)
+		}
+	}
+
+	// deletions are in a stable order as the live rules may not be
+	sort.Slice(changes.Delete, func(i, j int) bool {
+		return changes.Delete[i].ID < changes.Delete[j].ID
+	})
+	return changes
+}
+
+// Apply the changes to the rules of the auth. Rules are granted before others are
+// revoked so access isn't lost while the changes are applied.
+func Apply(a auth.Auth, c *Changes) error {
+	for _, r := range append(c.Create, c.Update...) {
+		if err := a.Grant(r); err != nil {
+			return err
+		}
+	}
+	for _, r := range c.Delete {
+		if err := a.Revoke(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// equal compares the rules, an empty list of conditions is the same as none
+func equal(a, b *auth.Rule) bool {
+	if len(a.Conditions) == 0 && len(b.Conditions) == 0 {
+		ac, bc := *a, *b
+		ac.Conditions, bc.Conditions = nil, nil
+		a, b = &ac, &bc
+	}
+	ab, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+	return string(ab) == string(bb)
+}
@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"fmt"
+	"sync"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/audit"
+	"c-z.dev/go-micro/auth/rules"
+)
+
+// Request is a recorded request the rules are evaluated against
+type Request struct {
+	Account  *auth.Account
+	Resource *auth.Resource
+}
+
+func (r *Request) String() string {
+	acc := "public"
+	if r.Account != nil {
+		acc = r.Account.ID
+	}
+	return fmt.Sprintf("%s %s %s by %s", r.Resource.Type, r.Resource.Name, r.Resource.Endpoint, acc)
+}
+
+// Decision is a request whose outcome changes with the declared rules
+type Decision struct {
+	Request *Request
+	// Before is the result of verifying the request with the live rules
+	Before error
+	// After is the result of verifying the request with the declared rules
+	After error
+}
+
+// Report of the changes and their effect on the recorded requests
+type Report struct {
+	Changes   *Changes
+	Decisions []*Decision
+}
+
+// DryRun evaluates the requests against the live and the declared rules and returns
+// the requests which would be granted or denied differently
+func DryRun(live, declared []*auth.Rule, reqs []*Request) []*Decision {
+	var decisions []*Decision
+	for _, r := range reqs {
+		before := rules.Verify(live, r.Account, r.Resource)
+		after := rules.Verify(declared, r.Account, r.Resource)
+		if (before == nil) != (after == nil) {
+			decisions = append(decisions, &Decision{Request: r, Before: before, After: after})
+		}
+	}
+	return decisions
+}
+
+// Recorder is an audit sink which keeps the most recent requests so changes to
+// the rules can be evaluated against them. Request metadata isn't recorded, so
+// conditions on it are evaluated as if it wasn't set.
+type Recorder struct {
+	sync.RWMutex
+	size     int
+	requests []*Request
+}
+
+// NewRecorder returns a recorder keeping up to size requests
+func NewRecorder(size int) *Recorder {
+	return &Recorder{size: size}
+}
+
+// Write records the request of the event
+func (r *Recorder) Write(e *audit.Event) error {
+	if e.Resource == nil {
+		return nil
+	}
+
+	req := &Request{Resource: e.Resource}
+	if len(e.Account) > 0 {
+		req.Account = &auth.Account{ID: e.Account, Issuer: e.Issuer, Scopes: e.Scopes}
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	r.requests = append(r.requests, req)
+	if len(r.requests) > r.size {
+		r.requests = r.requests[len(r.requests)-r.size:]
+	}
+	return nil
+}
+
+// Requests returns the recorded requests, oldest first
+func (r *Recorder) Requests() []*Request {
+	r.RLock()
+	defer r.RUnlock()
+	reqs := make([]*Request, len(r.requests))
+	copy(reqs, r.requests)
+	return reqs
+}
+
+func (r *Recorder) String() string {
+	return "policy"
+}
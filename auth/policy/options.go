@@ -0,0 +1,45 @@
+package policy
+
+type Options struct {
+	// Path of the rules in the config
+	Path []string
+	// Requests returns the recorded requests the changes are evaluated against
+	Requests func() []*Request
+	// Approve is called with the report of each change before it's applied,
+	// the change is rejected if it returns an error
+	Approve func(*Report) error
+	// AllowEmpty allows an empty rule set to replace the live rules
+	AllowEmpty bool
+}
+
+type Option func(o *Options)
+
+// Path sets the path of the rules in the config, it defaults to auth.rules
+func Path(path ...string) Option {
+	return func(o *Options) {
+		o.Path = path
+	}
+}
+
+// Requests sets the recorded requests changes are dry run against, e.g. the
+// requests of a Recorder
+func Requests(fn func() []*Request) Option {
+	return func(o *Options) {
+		o.Requests = fn
+	}
+}
+
+// Approve sets the func which approves changes before they're applied
+func Approve(fn func(*Report) error) Option {
+	return func(o *Options) {
+		o.Approve = fn
+	}
+}
+
+// AllowEmpty allows declaring no rules to delete all the live rules, without it
+// an empty rule set is rejected
+func AllowEmpty() Option {
+	return func(o *Options) {
+		o.AllowEmpty = true
+	}
+}
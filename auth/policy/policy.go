@@ -0,0 +1,189 @@
+// Package policy declares auth rules as code. Rules are loaded from config, validated,
+// evaluated against recorded requests and applied to the live rules when they change.
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/config/reader"
+)
+
+// Rule is the declared form of an auth.Rule, e.g. in yaml
+//
+//	rules:
+//	  - id: admins
+//	    scope: admin
+//	    resource: {type: service, name: "*", endpoint: "*"}
+//	    access: granted
+//	    priority: 10
+//	    conditions:
+//	      - {attribute: time.clock, operator: between, values: ["09:00", "17:00"]}
+type Rule struct {
+	ID         string        `json:"id"`
+	Scope      string        `json:"scope"`
+	Resource   auth.Resource `json:"resource"`
+	Access     string        `json:"access"`
+	Priority   int32         `json:"priority"`
+	Conditions []Condition   `json:"conditions,omitempty"`
+}
+
+// Condition is the declared form of an auth.Condition
+type Condition struct {
+	Attribute string   `json:"attribute"`
+	Operator  string   `json:"operator"`
+	Values    []string `json:"values"`
+}
+
+const (
+	accessGranted = "granted"
+	accessDenied  = "denied"
+)
+
+// Parse the rules declared in a config value and validate them. A missing
+// value is an error so a mistyped path doesn't declare zero rules.
+func Parse(v reader.Value) ([]*auth.Rule, error) {
+	if b := bytes.TrimSpace(v.Bytes()); len(b) == 0 || string(b) == "null" {
+		return nil, errors.New("no rules declared")
+	}
+
+	var declared []*Rule
+	if err := v.Scan(&declared); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+
+	rules := make([]*auth.Rule, 0, len(declared))
+	for i, r := range declared {
+		if r == nil {
+			return nil, fmt.Errorf("rule %d is empty", i)
+		}
+		rule, err := r.rule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := Validate(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// rule converts the declared rule
+func (r *Rule) rule() (*auth.Rule, error) {
+	rule := &auth.Rule{
+		ID:       r.ID,
+		Scope:    r.Scope,
+		Resource: &auth.Resource{Type: r.Resource.Type, Name: r.Resource.Name, Endpoint: r.Resource.Endpoint},
+		Priority: r.Priority,
+	}
+
+	switch strings.ToLower(r.Access) {
+	case accessGranted:
+		rule.Access = auth.AccessGranted
+	case accessDenied:
+		rule.Access = auth.AccessDenied
+	case "":
+		return nil, fmt.Errorf("rule %s: access is required, expected granted or denied", r.ID)
+	default:
+		return nil, fmt.Errorf("rule %s: unknown access %q, expected granted or denied", r.ID, r.Access)
+	}
+
+	for _, c := range r.Conditions {
+		rule.Conditions = append(rule.Conditions, &auth.Condition{
+			Attribute: c.Attribute,
+			Operator:  auth.Operator(strings.ToLower(c.Operator)),
+			Values:    c.Values,
+		})
+	}
+	return rule, nil
+}
+
+// Validate the rules, each rule requires a unique id, a resource and valid conditions
+func Validate(rules []*auth.Rule) error {
+	ids := make(map[string]bool, len(rules))
+
+	for i, r := range rules {
+		if r == nil {
+			return fmt.Errorf("rule %d is empty", i)
+		}
+		if len(r.ID) == 0 {
+			return fmt.Errorf("rule %d requires an id", i)
+		}
+		if ids[r.ID] {
+			return fmt.Errorf("rule %s is declared more than once", r.ID)
+		}
+		ids[r.ID] = true
+
+		if r.Resource == nil || len(r.Resource.Type) == 0 || len(r.Resource.Name) == 0 || len(r.Resource.Endpoint) == 0 {
+			return fmt.Errorf("rule %s requires a resource type, name and endpoint, use * to match any", r.ID)
+		}
+		if r.Access != auth.AccessGranted && r.Access != auth.AccessDenied {
+			return fmt.Errorf("rule %s has unknown access %d", r.ID, r.Access)
+		}
+
+		for _, c := range r.Conditions {
+			if err := validateCondition(c); err != nil {
+				return fmt.Errorf("rule %s: %w", r.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// attributes conditions can be evaluated against, the ones ending in . are prefixes
+var attributes = []string{
+	"account.id", "account.type", "account.issuer", "account.metadata.",
+	"metadata.", "resource.name", "resource.type", "resource.endpoint",
+	"time", "time.clock", "time.weekday",
+}
+
+func validateCondition(c *auth.Condition) error {
+	if c == nil {
+		return fmt.Errorf("empty condition")
+	}
+	if !validAttribute(c.Attribute) {
+		return fmt.Errorf("unknown attribute %q", c.Attribute)
+	}
+	for _, v := range c.Values {
+		if strings.HasPrefix(v, "$") && !validAttribute(strings.TrimPrefix(v, "$")) {
+			return fmt.Errorf("unknown attribute %q referenced", v)
+		}
+	}
+
+	var n int
+	switch c.Operator {
+	case auth.OperatorEqual, auth.OperatorNotEqual:
+		n = 1
+	case auth.OperatorBetween:
+		n = 2
+	case auth.OperatorExists:
+		n = 0
+	case auth.OperatorIn, auth.OperatorNotIn, auth.OperatorPrefix:
+		if len(c.Values) == 0 {
+			return fmt.Errorf("%s %s requires at least one value", c.Attribute, c.Operator)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown operator %q", c.Operator)
+	}
+
+	if len(c.Values) != n {
+		return fmt.Errorf("%s %s requires %d values, got %d", c.Attribute, c.Operator, n, len(c.Values))
+	}
+	return nil
+}
+
+func validAttribute(a string) bool {
+	for _, attr := range attributes {
+		if a == attr || (strings.HasSuffix(attr, ".") && strings.HasPrefix(a, attr) && len(a) > len(attr)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/audit"
+	as "c-z.dev/go-micro/auth/store"
+	"c-z.dev/go-micro/config"
+	"c-z.dev/go-micro/config/source"
+	"c-z.dev/go-micro/config/source/memory"
+	mstore "c-z.dev/go-micro/store/memory"
+)
+
+var rulesJSON = []byte(`{"auth": {"rules": [
+	{
+		"id": "public",
+		"resource": {"type": "service", "name": "go.micro.service.foo", "endpoint": "Foo.Public"},
+		"access": "granted"
+	},
+	{
+		"id": "admins",
+		"scope": "admin",
+		"resource": {"type": "service", "name": "*", "endpoint": "*"},
+		"access": "granted",
+		"priority": 10,
+		"conditions": [
+			{"attribute": "time.weekday", "operator": "in", "values": ["Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"]}
+		]
+	}
+]}}`)
+
+func TestParse(t *testing.T) {
+	c, err := config.NewConfig(config.WithSource(memory.NewSource(memory.WithJSON(rulesJSON))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := Parse(c.Get("auth", "rules"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules but got %v", len(rules))
+	}
+	if r := rules[1]; r.ID != "admins" || r.Scope != "admin" || r.Priority != 10 || r.Access != auth.AccessGranted {
+		t.Fatalf("Unexpected rule %+v", r)
+	}
+	if c := rules[1].Conditions; len(c) != 1 || c[0].Operator != auth.OperatorIn || len(c[0].Values) != 7 {
+		t.Fatalf("Unexpected conditions %+v", c)
+	}
+
+	// access must be declared
+	c, err = config.NewConfig(config.WithSource(memory.NewSource(memory.WithJSON([]byte(
+		`{"auth": {"rules": [{"id": "a", "resource": {"type": "*", "name": "*", "endpoint": "*"}}]}}`,
+	)))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Parse(c.Get("auth", "rules")); err == nil {
+		t.Fatal("Expected an error for a rule without access")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	res := &auth.Resource{Type: "*", Name: "*", Endpoint: "*"}
+
+	tt := []struct {
+		Name  string
+		Rules []*auth.Rule
+		Valid bool
+	}{
+		{Name: "Valid", Rules: []*auth.Rule{{ID: "a", Resource: res}}, Valid: true},
+		{Name: "MissingID", Rules: []*auth.Rule{{Resource: res}}},
+		{Name: "DuplicateID", Rules: []*auth.Rule{{ID: "a", Resource: res}, {ID: "a", Resource: res}}},
+		{Name: "MissingResource", Rules: []*auth.Rule{{ID: "a"}}},
+		{Name: "PartialResource", Rules: []*auth.Rule{{ID: "a", Resource: &auth.Resource{Type: "service"}}}},
+		{
+			Name: "UnknownAttribute",
+			Rules: []*auth.Rule{{ID: "a", Resource: res, Conditions: []*auth.Condition{
+				{Attribute: "account.name", Operator: auth.OperatorEqual, Values: []string{"john"}},
+			}}},
+		},
+		{
+			Name: "UnknownReference",
+			Rules: []*auth.Rule{{ID: "a", Resource: res, Conditions: []*auth.Condition{
+				{Attribute: "account.id", Operator: auth.OperatorEqual, Values: []string{"$foo"}},
+			}}},
+		},
+		{
+			Name: "UnknownOperator",
+			Rules: []*auth.Rule{{ID: "a", Resource: res, Conditions: []*auth.Condition{
+				{Attribute: "account.id", Operator: "~=", Values: []string{"john"}},
+			}}},
+		},
+		{
+			Name: "BetweenValues",
+			Rules: []*auth.Rule{{ID: "a", Resource: res, Conditions: []*auth.Condition{
+				{Attribute: "time.clock", Operator: auth.OperatorBetween, Values: []string{"09:00"}},
+			}}},
+		},
+		{
+			Name: "ValidConditions",
+			Rules: []*auth.Rule{{ID: "a", Resource: res, Conditions: []*auth.Condition{
+				{Attribute: "account.metadata.tenant", Operator: auth.OperatorEqual, Values: []string{"$metadata.Micro-Tenant"}},
+				{Attribute: "metadata.Micro-Tenant", Operator: auth.OperatorExists},
+			}}},
+			Valid: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if err := Validate(tc.Rules); (err == nil) != tc.Valid {
+				t.Fatalf("Expected valid %v but got %v", tc.Valid, err)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	res := &auth.Resource{Type: "*", Name: "*", Endpoint: "*"}
+	live := []*auth.Rule{
+		{ID: "same", Scope: "*", Resource: res},
+		{ID: "changed", Scope: "*", Resource: res},
+		{ID: "removed", Scope: "*", Resource: res},
+	}
+	declared := []*auth.Rule{
+		{ID: "same", Scope: "*", Resource: res, Conditions: []*auth.Condition{}},
+		{ID: "changed", Scope: "admin", Resource: res},
+		{ID: "added", Resource: res},
+	}
+
+	c := Diff(live, declared)
+	if len(c.Create) != 1 || c.Create[0].ID != "added" {
+		t.Errorf("Expected added to be created but got %v", c.Create)
+	}
+	if len(c.Update) != 1 || c.Update[0].ID != "changed" {
+		t.Errorf("Expected changed to be updated but got %v", c.Update)
+	}
+	if len(c.Delete) != 1 || c.Delete[0].ID != "removed" {
+		t.Errorf("Expected removed to be deleted but got %v", c.Delete)
+	}
+	if !Diff(declared, declared).Empty() {
+		t.Errorf("Expected no changes")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	src := memory.NewSource(memory.WithJSON(rulesJSON))
+	c, err := config.NewConfig(config.WithSource(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := as.NewAuth(auth.Store(mstore.NewStore()), auth.Namespace("go.micro"))
+	if err := a.Grant(&auth.Rule{ID: "legacy", Scope: "*", Resource: &auth.Resource{Type: "*", Name: "*", Endpoint: "*"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the requests are recorded by an auditor
+	rec := NewRecorder(10)
+	auditor := audit.NewAuditor(audit.WithSink(rec), audit.SampleRate(1))
+	acc := &auth.Account{ID: "john", Scopes: []string{"user"}}
+	res := &auth.Resource{Type: "service", Name: "go.micro.service.foo", Endpoint: "Foo.Bar"}
+	ctx := audit.NewContext(context.Background(), auditor)
+	if err := a.Verify(acc, res, auth.VerifyContext(ctx)); err != nil {
+		t.Fatal(err)
+	}
+	auditor.Flush()
+
+	var reports []*Report
+	approved := int32(1)
+	approve := func(r *Report) error {
+		reports = append(reports, r)
+		if atomic.LoadInt32(&approved) == 0 {
+			return errors.New("rejected")
+		}
+		return nil
+	}
+
+	w, err := Watch(c, a, Requests(rec.Requests), Approve(approve))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// the legacy rule is replaced, so john loses access
+	if len(reports) != 1 || len(reports[0].Decisions) != 1 || reports[0].Decisions[0].After == nil {
+		t.Fatalf("Expected john to lose access but got %+v", reports)
+	}
+	if rules, _ := a.Rules(); len(rules) != 2 {
+		t.Fatalf("Expected 2 rules but got %v", len(rules))
+	}
+
+	// wait for the source to be watched
+	time.Sleep(100 * time.Millisecond)
+
+	// invalid and rejected changes aren't applied
+	atomic.StoreInt32(&approved, 0)
+	src.Write(&source.ChangeSet{Data: []byte(`{"auth": {"rules": [{"id": "a"}]}}`), Format: "json"})
+	src.Write(&source.ChangeSet{Data: []byte(`{"auth": {"rules": [{"id": "public", "resource": {"type": "*", "name": "*", "endpoint": "*"}, "access": "granted"}]}}`), Format: "json"})
+	time.Sleep(100 * time.Millisecond)
+	if rules, _ := a.Rules(); len(rules) != 2 {
+		t.Fatalf("Expected 2 rules but got %v", len(rules))
+	}
+
+	// approved changes are hot applied
+	atomic.StoreInt32(&approved, 1)
+	src.Write(&source.ChangeSet{Data: []byte(`{"auth": {"rules": [
+		{"id": "public", "resource": {"type": "*", "name": "*", "endpoint": "*"}, "access": "granted"},
+		{"id": "users", "scope": "user", "resource": {"type": "*", "name": "*", "endpoint": "*"}, "access": "granted"}
+	]}}`), Format: "json"})
+	time.Sleep(100 * time.Millisecond)
+
+	rules, err := a.Rules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules but got %v", len(rules))
+	}
+	if err := a.Verify(acc, res); err != nil {
+		t.Fatalf("Expected john to be granted access but got %v", err)
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	a := as.NewAuth(auth.Store(mstore.NewStore()), auth.Namespace("go.micro"))
+	if err := a.Grant(&auth.Rule{ID: "legacy", Scope: "*", Resource: &auth.Resource{Type: "*", Name: "*", Endpoint: "*"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	newConfig := func(data string) config.Config {
+		c, err := config.NewConfig(config.WithSource(memory.NewSource(memory.WithJSON([]byte(data)))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	// missing rules aren't zero rules
+	if _, err := Load(newConfig(`{"auth": {}}`), a); err == nil {
+		t.Fatal("Expected an error for missing rules")
+	}
+	if _, err := Load(newConfig(`{"auth": {"rules": null}}`), a); err == nil {
+		t.Fatal("Expected an error for null rules")
+	}
+
+	// an empty rule set only replaces the live rules when allowed
+	empty := newConfig(`{"auth": {"rules": []}}`)
+	if _, err := Load(empty, a); err == nil {
+		t.Fatal("Expected an error for an empty rule set")
+	}
+	if rules, _ := a.Rules(); len(rules) != 1 {
+		t.Fatalf("Expected the live rule to be kept but got %v rules", len(rules))
+	}
+	if _, err := Load(empty, a, AllowEmpty()); err != nil {
+		t.Fatal(err)
+	}
+	if rules, _ := a.Rules(); len(rules) != 0 {
+		t.Fatalf("Expected no rules but got %v", len(rules))
+	}
+}
@@ -0,0 +1,160 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/config"
+	"c-z.dev/go-micro/config/reader"
+	"c-z.dev/go-micro/config/source"
+	"c-z.dev/go-micro/logger"
+	"c-z.dev/go-micro/util/backoff"
+)
+
+// Watcher applies the rules declared in config until stopped
+type Watcher interface {
+	Stop() error
+}
+
+type watcher struct {
+	opts   Options
+	auth   auth.Auth
+	config config.Watcher
+
+	sync.Once
+	exit chan bool
+}
+
+// Load the rules declared in the config and apply them to the auth. The declared
+// rules replace all of the live ones. The report of the changes is returned, no
+// changes are applied if the rules are invalid or the change isn't approved.
+func Load(c config.Config, a auth.Auth, opts ...Option) (*Report, error) {
+	options := newOptions(opts...)
+	return apply(c.Get(options.Path...), a, options)
+}
+
+// Watch loads the rules declared in the config and applies them each time they change.
+// Invalid or rejected changes are logged and the last applied rules kept.
+func Watch(c config.Config, a auth.Auth, opts ...Option) (Watcher, error) {
+	options := newOptions(opts...)
+
+	if _, err := apply(c.Get(options.Path...), a, options); err != nil {
+		return nil, err
+	}
+
+	cw, err := c.Watch(options.Path...)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &watcher{
+		opts:   options,
+		auth:   a,
+		config: cw,
+		exit:   make(chan bool),
+	}
+	go w.run()
+	return w, nil
+}
+
+func (w *watcher) run() {
+	var attempts int
+
+	for {
+		v, err := w.config.Next()
+
+		select {
+		case <-w.exit:
+			return
+		default:
+		}
+
+		if err == source.ErrWatcherStopped {
+			return
+		}
+		if err != nil {
+			attempts++
+			logger.Errorf("Error watching auth rules: %v", err)
+			select {
+			case <-w.exit:
+				return
+			case <-time.After(backoff.Do(attempts)):
+			}
+			continue
+		}
+		attempts = 0
+
+		if _, err := apply(v, w.auth, w.opts); err != nil {
+			logger.Errorf("Error applying auth rules: %v", err)
+		}
+	}
+}
+
+func (w *watcher) Stop() error {
+	w.Do(func() {
+		close(w.exit)
+	})
+	return w.config.Stop()
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Path: []string{"auth", "rules"},
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// apply the declared rules once they're validated, dry run and approved
+func apply(v reader.Value, a auth.Auth, opts Options) (*Report, error) {
+	declared, err := Parse(v)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := a.Rules()
+	if err != nil {
+		return nil, err
+	}
+	if len(declared) == 0 && len(live) > 0 && !opts.AllowEmpty {
+		return nil, errors.New("no rules declared, deleting all the live rules requires AllowEmpty")
+	}
+
+	report := &Report{Changes: Diff(live, declared)}
+	if report.Changes.Empty() {
+		return report, nil
+	}
+
+	if opts.Requests != nil {
+		report.Decisions = DryRun(live, declared, opts.Requests())
+		for _, d := range report.Decisions {
+			logger.Infof("Auth rules change: %v would be %v", d.Request, decision(d.After))
+		}
+	}
+
+	if opts.Approve != nil {
+		if err := opts.Approve(report); err != nil {
+			return report, fmt.Errorf("auth rules change rejected: %w", err)
+		}
+	}
+
+	if err := Apply(a, report.Changes); err != nil {
+		return report, err
+	}
+
+	logger.Infof("Applied auth rules: %d created, %d updated, %d deleted",
+		len(report.Changes.Create), len(report.Changes.Update), len(report.Changes.Delete))
+	return report, nil
+}
+
+func decision(err error) string {
+	if err == nil {
+		return "granted"
+	}
+	return "denied"
+}
@@ -45,6 +45,11 @@ func (o *oauth) Endpoint(opts ...provider.EndpointOption) string {
 		params.Add("login_hint", options.LoginHint)
 	}
 
+	if len(options.CodeChallenge) > 0 {
+		params.Add("code_challenge", options.CodeChallenge)
+		params.Add("code_challenge_method", "S256")
+	}
+
 	if clientID := o.opts.ClientID; len(clientID) > 0 {
 		params.Add("client_id", clientID)
 	}
@@ -0,0 +1,111 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"c-z.dev/go-micro/auth/token/jwt"
+)
+
+// keySet fetches and caches the issuer's signing keys
+type keySet struct {
+	sync.Mutex
+	client *http.Client
+	url    string
+	ttl    time.Duration
+
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// minRefresh limits how often unknown key ids cause the keys to be refetched
+const minRefresh = time.Second * 10
+
+// key returns the public key with the id, refetching the keys
+// when they've expired or the id is unknown e.g. after rotation
+func (k *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	k.Lock()
+	defer k.Unlock()
+
+	if time.Since(k.fetched) > k.ttl {
+		if err := k.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+
+	if time.Since(k.fetched) > minRefresh {
+		if err := k.fetch(ctx); err != nil {
+			return nil, err
+		}
+		if key, ok := k.lookup(kid); ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("signing key %q not found", kid)
+}
+
+func (k *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if key, ok := k.keys[kid]; ok {
+		return key, true
+	}
+	// tokens without a key id can be verified by a sole key
+	if len(kid) == 0 && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func (k *keySet) fetch(ctx context.Context) error {
+	var set jwt.JWKS
+	if err := getJSON(ctx, k.client, k.url, &set); err != nil {
+		return fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if len(jwk.Use) > 0 && jwk.Use != "sig" {
+			continue
+		}
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = pub
+	}
+
+	k.keys = keys
+	k.fetched = time.Now()
+	return nil
+}
+
+func getJSON(ctx context.Context, c *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	rsp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, rsp.Status)
+	}
+	return json.NewDecoder(rsp.Body).Decode(v)
+}
@@ -0,0 +1,330 @@
+// Package oidc is an OpenID Connect auth provider. It discovers the issuer's
+// configuration, exchanges authorization codes using PKCE, verifies ID tokens
+// against the issuer's signing keys and maps their claims to an account.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/provider"
+)
+
+var (
+	// DefaultScope requested from the issuer
+	DefaultScope = "openid profile email"
+	// DefaultKeyCacheTTL is how long signing keys are cached for
+	DefaultKeyCacheTTL = time.Hour
+	// DefaultTimeout for requests to the issuer
+	DefaultTimeout = time.Second * 10
+)
+
+// Provider is an OpenID Connect provider
+type Provider interface {
+	provider.Provider
+	// Discover returns the issuer's configuration
+	Discover(ctx context.Context) (*Discovery, error)
+	// Exchange an authorization code for tokens, verifier is the PKCE code verifier
+	Exchange(ctx context.Context, code, verifier string) (*Tokens, error)
+	// Verify an ID token and return its claims, the nonce is checked if not blank
+	Verify(ctx context.Context, idToken, nonce string) (Claims, error)
+	// Account maps verified claims to an account
+	Account(claims Claims) *auth.Account
+}
+
+// Discovery is the issuer's OpenID configuration
+type Discovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	UserinfoEndpoint      string   `json:"userinfo_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	ScopesSupported       []string `json:"scopes_supported"`
+}
+
+// Tokens returned by the issuer's token endpoint
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+type oidc struct {
+	opts   provider.Options
+	client *http.Client
+	ttl    time.Duration
+	scopes []claimScopes
+
+	sync.Mutex
+	discovery *Discovery
+	keys      *keySet
+}
+
+// NewProvider returns an OpenID Connect provider, the endpoint option is the issuer url
+func NewProvider(opts ...provider.Option) Provider {
+	var options provider.Options
+	for _, o := range opts {
+		o(&options)
+	}
+	if len(options.Scope) == 0 {
+		options.Scope = DefaultScope
+	}
+
+	o := &oidc{
+		opts:   options,
+		client: &http.Client{Timeout: DefaultTimeout},
+		ttl:    DefaultKeyCacheTTL,
+	}
+	if c := options.Context; c != nil {
+		if hc, ok := c.Value(httpClientKey{}).(*http.Client); ok && hc != nil {
+			o.client = hc
+		}
+		if d, ok := c.Value(keyCacheKey{}).(time.Duration); ok && d > 0 {
+			o.ttl = d
+		}
+		o.scopes, _ = c.Value(claimScopesKey{}).([]claimScopes)
+	}
+	return o
+}
+
+func (o *oidc) String() string {
+	return "oidc"
+}
+
+func (o *oidc) Options() provider.Options {
+	return o.opts
+}
+
+func (o *oidc) Redirect() string {
+	return o.opts.Redirect
+}
+
+// Endpoint returns the authorization url. The issuer's configuration is
+// discovered if it hasn't been, a blank url is returned if that fails.
+func (o *oidc) Endpoint(opts ...provider.EndpointOption) string {
+	var options provider.EndpointOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	d, err := o.Discover(context.Background())
+	if err != nil {
+		return ""
+	}
+
+	params := make(url.Values)
+	params.Add("response_type", "code")
+	params.Add("client_id", o.opts.ClientID)
+	params.Add("scope", o.opts.Scope)
+
+	if len(options.State) > 0 {
+		params.Add("state", options.State)
+	}
+	if len(options.Nonce) > 0 {
+		params.Add("nonce", options.Nonce)
+	}
+	if len(options.LoginHint) > 0 {
+		params.Add("login_hint", options.LoginHint)
+	}
+	if len(options.CodeChallenge) > 0 {
+		params.Add("code_challenge", options.CodeChallenge)
+		params.Add("code_challenge_method", "S256")
+	}
+	if redir := o.Redirect(); len(redir) > 0 {
+		params.Add("redirect_uri", redir)
+	}
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + params.Encode()
+}
+
+func (o *oidc) Discover(ctx context.Context) (*Discovery, error) {
+	o.Lock()
+	defer o.Unlock()
+
+	if o.discovery != nil {
+		return o.discovery, nil
+	}
+
+	issuer := strings.TrimSuffix(o.opts.Endpoint, "/")
+	if len(issuer) == 0 {
+		return nil, errors.New("no issuer url is defined")
+	}
+
+	var d *Discovery
+	if err := getJSON(ctx, o.client, issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, fmt.Errorf("failed to discover issuer: %w", err)
+	}
+	if d.Issuer != issuer {
+		return nil, fmt.Errorf("issuer %q doesn't match %q", d.Issuer, issuer)
+	}
+	if len(d.AuthorizationEndpoint) == 0 || len(d.TokenEndpoint) == 0 || len(d.JWKSURI) == 0 {
+		return nil, errors.New("issuer configuration is incomplete")
+	}
+
+	o.discovery = d
+	o.keys = &keySet{client: o.client, url: d.JWKSURI, ttl: o.ttl}
+	return d, nil
+}
+
+func (o *oidc) Exchange(ctx context.Context, code, verifier string) (*Tokens, error) {
+	d, err := o.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	if redir := o.Redirect(); len(redir) > 0 {
+		form.Set("redirect_uri", redir)
+	}
+	if len(verifier) > 0 {
+		form.Set("code_verifier", verifier)
+	}
+	if len(o.opts.ClientSecret) == 0 {
+		form.Set("client_id", o.opts.ClientID)
+	}
+
+	req, err := http.NewRequest("POST", d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if len(o.opts.ClientSecret) > 0 {
+		req.SetBasicAuth(url.QueryEscape(o.opts.ClientID), url.QueryEscape(o.opts.ClientSecret))
+	}
+
+	rsp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		var e struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		json.NewDecoder(rsp.Body).Decode(&e)
+		if len(e.Error) > 0 {
+			return nil, fmt.Errorf("token exchange failed: %s %s", e.Error, e.Description)
+		}
+		return nil, fmt.Errorf("token exchange failed: %s", rsp.Status)
+	}
+
+	var t *Tokens
+	if err := json.NewDecoder(rsp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	if len(t.IDToken) == 0 {
+		return nil, errors.New("token response has no id token")
+	}
+	return t, nil
+}
+
+func (o *oidc) Verify(ctx context.Context, idToken, nonce string) (Claims, error) {
+	d, err := o.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := verifySignature(ctx, o.keys, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.String("iss") != d.Issuer {
+		return nil, fmt.Errorf("%w: wrong issuer", ErrInvalidIDToken)
+	}
+
+	aud := claims.Strings("aud")
+	if !include(aud, o.opts.ClientID) {
+		return nil, fmt.Errorf("%w: wrong audience", ErrInvalidIDToken)
+	}
+	if azp := claims.String("azp"); len(aud) > 1 && len(azp) > 0 && azp != o.opts.ClientID {
+		return nil, fmt.Errorf("%w: wrong authorized party", ErrInvalidIDToken)
+	}
+
+	now := time.Now()
+	exp, ok := claims.Time("exp")
+	if !ok || now.After(exp.Add(leeway)) {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidIDToken)
+	}
+	if iat, ok := claims.Time("iat"); ok && iat.After(now.Add(leeway)) {
+		return nil, fmt.Errorf("%w: issued in the future", ErrInvalidIDToken)
+	}
+
+	if len(nonce) > 0 && claims.String("nonce") != nonce {
+		return nil, fmt.Errorf("%w: wrong nonce", ErrInvalidIDToken)
+	}
+
+	if len(claims.String("sub")) == 0 {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+func (o *oidc) Account(claims Claims) *auth.Account {
+	md := map[string]string{
+		"provider": o.String(),
+	}
+	for _, c := range []string{"email", "name", "preferred_username"} {
+		if v := claims.String(c); len(v) > 0 {
+			md[c] = v
+		}
+	}
+
+	var scopes []string
+	for _, cs := range o.scopes {
+		for _, v := range claims.Strings(cs.claim) {
+			if cs.mapping == nil {
+				scopes = appendUnique(scopes, v)
+				continue
+			}
+			for _, s := range cs.mapping[v] {
+				scopes = appendUnique(scopes, s)
+			}
+		}
+	}
+
+	return &auth.Account{
+		ID:       claims.String("sub"),
+		Type:     "user",
+		Issuer:   claims.String("iss"),
+		Metadata: md,
+		Scopes:   scopes,
+	}
+}
+
+func include(slice []string, v string) bool {
+	for _, s := range slice {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(slice []string, v string) []string {
+	if include(slice, v) {
+		return slice
+	}
+	return append(slice, v)
+}
@@ -0,0 +1,194 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/auth/provider"
+	"c-z.dev/go-micro/auth/token/jwt"
+)
+
+// issuer is a fake OpenID Connect issuer
+type issuer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	sync.Mutex
+	// codes issued mapped to the authorize request
+	codes map[string]url.Values
+}
+
+func newIssuer(t *testing.T) *issuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	is := &issuer{key: key, codes: make(map[string]url.Values)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&Discovery{
+			Issuer:                is.URL,
+			AuthorizationEndpoint: is.URL + "/authorize",
+			TokenEndpoint:         is.URL + "/token",
+			JWKSURI:               is.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []*jwt.JWK{{
+				Kid: "1",
+				Kty: "RSA",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		code := NewVerifier()
+		is.Lock()
+		is.codes[code] = q
+		is.Unlock()
+
+		redir, _ := url.Parse(q.Get("redirect_uri"))
+		rq := redir.Query()
+		rq.Set("code", code)
+		rq.Set("state", q.Get("state"))
+		redir.RawQuery = rq.Encode()
+		http.Redirect(w, r, redir.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		is.Lock()
+		q, ok := is.codes[r.Form.Get("code")]
+		delete(is.codes, r.Form.Get("code"))
+		is.Unlock()
+
+		if !ok || Challenge(r.Form.Get("code_verifier")) != q.Get("code_challenge") {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(&Tokens{
+			AccessToken: "access",
+			TokenType:   "Bearer",
+			IDToken: is.sign(t, map[string]interface{}{
+				"iss":    is.URL,
+				"sub":    "user-1",
+				"aud":    q.Get("client_id"),
+				"exp":    time.Now().Add(time.Hour).Unix(),
+				"iat":    time.Now().Unix(),
+				"nonce":  q.Get("nonce"),
+				"email":  "user@example.com",
+				"groups": []string{"admins", "staff"},
+			}),
+		})
+	})
+
+	is.Server = httptest.NewServer(mux)
+	return is
+}
+
+func (is *issuer) sign(t *testing.T, claims map[string]interface{}) string {
+	h, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "1", "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	d := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, is.key, crypto.SHA256, d[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestProvider(t *testing.T) {
+	is := newIssuer(t)
+	defer is.Close()
+
+	p := NewProvider(
+		provider.Endpoint(is.URL),
+		provider.Credentials("micro", ""),
+		provider.Redirect("http://localhost/callback"),
+		ClaimScopes("groups", map[string][]string{"admins": {"admin"}}),
+	)
+
+	verifier := NewVerifier()
+	endpoint := p.Endpoint(
+		provider.WithState("state"),
+		provider.WithNonce("nonce"),
+		provider.WithCodeChallenge(Challenge(verifier)),
+	)
+	if !strings.HasPrefix(endpoint, is.URL+"/authorize?") {
+		t.Fatalf("Unexpected endpoint %s", endpoint)
+	}
+
+	// authorize without following the redirect back
+	c := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	rsp, err := c.Get(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, _ := url.Parse(rsp.Header.Get("Location"))
+	code := loc.Query().Get("code")
+
+	ctx := context.Background()
+
+	// the wrong verifier is rejected
+	if _, err := p.Exchange(ctx, code, NewVerifier()); err == nil {
+		t.Fatal("Expected exchange to fail with the wrong verifier")
+	}
+
+	rsp, _ = c.Get(endpoint)
+	loc, _ = url.Parse(rsp.Header.Get("Location"))
+	tokens, err := p.Exchange(ctx, loc.Query().Get("code"), verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.Verify(ctx, tokens.IDToken, "other"); err == nil {
+		t.Fatal("Expected verification to fail with the wrong nonce")
+	}
+	claims, err := p.Verify(ctx, tokens.IDToken, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acc := p.Account(claims)
+	if acc.ID != "user-1" || acc.Issuer != is.URL || acc.Metadata["email"] != "user@example.com" {
+		t.Fatalf("Unexpected account %+v", acc)
+	}
+	if len(acc.Scopes) != 1 || acc.Scopes[0] != "admin" {
+		t.Fatalf("Expected admin scope got %v", acc.Scopes)
+	}
+
+	// tokens for other clients, expired or tampered tokens are rejected
+	bad := []string{
+		is.sign(t, map[string]interface{}{"iss": is.URL, "sub": "1", "aud": "other", "exp": time.Now().Add(time.Hour).Unix()}),
+		is.sign(t, map[string]interface{}{"iss": is.URL, "sub": "1", "aud": "micro", "exp": time.Now().Add(-time.Hour).Unix()}),
+		is.sign(t, map[string]interface{}{"iss": "https://evil", "sub": "1", "aud": "micro", "exp": time.Now().Add(time.Hour).Unix()}),
+		tokens.IDToken[:len(tokens.IDToken)-4] + "AAAA",
+	}
+	for i, tok := range bad {
+		if _, err := p.Verify(ctx, tok, ""); err == nil {
+			t.Fatalf("Expected token %d to fail verification", i)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"c-z.dev/go-micro/auth/provider"
+)
+
+type httpClientKey struct{}
+type claimScopesKey struct{}
+type keyCacheKey struct{}
+
+// claimScopes maps the values of a claim to scopes
+type claimScopes struct {
+	claim   string
+	mapping map[string][]string
+}
+
+// HTTPClient sets the client used to talk to the issuer
+func HTTPClient(c *http.Client) provider.Option {
+	return setOption(httpClientKey{}, c)
+}
+
+// ClaimScopes maps the values of an ID token claim to account scopes, e.g.
+// ClaimScopes("groups", map[string][]string{"admins": {"admin"}}). Values
+// without a mapping are ignored, if the mapping is nil the values are used
+// as scopes directly. It can be set multiple times for different claims.
+func ClaimScopes(claim string, mapping map[string][]string) provider.Option {
+	return func(o *provider.Options) {
+		var cs []claimScopes
+		if o.Context != nil {
+			cs, _ = o.Context.Value(claimScopesKey{}).([]claimScopes)
+		}
+		cs = append(cs[:len(cs):len(cs)], claimScopes{claim, mapping})
+		setOption(claimScopesKey{}, cs)(o)
+	}
+}
+
+// KeyCacheTTL sets how long the issuer's signing keys are cached for
+func KeyCacheTTL(d time.Duration) provider.Option {
+	return setOption(keyCacheKey{}, d)
+}
+
+func setOption(k, v interface{}) provider.Option {
+	return func(o *provider.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
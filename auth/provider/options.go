@@ -1,5 +1,7 @@
 package provider
 
+import "context"
+
 // Option returns a function which sets an option
 type Option func(*Options)
 
@@ -15,6 +17,8 @@ type Options struct {
 	Redirect string
 	// Scope of the oauth request
 	Scope string
+	// Context for other opts
+	Context context.Context
 }
 
 // Credentials is an option which sets the client id and secret
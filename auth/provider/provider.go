@@ -32,6 +32,10 @@ type EndpointOptions struct {
 	State string
 	// LoginHint prefils the user id on oauth clients
 	LoginHint string
+	// CodeChallenge is the S256 PKCE challenge of the code verifier
+	CodeChallenge string
+	// Nonce binds the ID token to the request
+	Nonce string
 }
 
 type EndpointOption func(*EndpointOptions)
@@ -47,3 +51,17 @@ func WithLoginHint(hint string) EndpointOption {
 		o.LoginHint = hint
 	}
 }
+
+// WithCodeChallenge sets the S256 PKCE code challenge
+func WithCodeChallenge(c string) EndpointOption {
+	return func(o *EndpointOptions) {
+		o.CodeChallenge = c
+	}
+}
+
+// WithNonce sets the nonce expected in the ID token
+func WithNonce(n string) EndpointOption {
+	return func(o *EndpointOptions) {
+		o.Nonce = n
+	}
+}
@@ -0,0 +1,29 @@
+package rules
+
+import (
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/audit"
+)
+
+// event builds the audit event for the decision made on the request
+func (r *request) event(rule *auth.Rule, err error) *audit.Event {
+	e := &audit.Event{
+		Timestamp: r.now,
+		Resource:  r.res,
+		Decision:  audit.DecisionGranted,
+	}
+	if r.acc != nil {
+		e.Account = r.acc.ID
+		e.Issuer = r.acc.Issuer
+		e.Scopes = r.acc.Scopes
+	}
+	if rule != nil {
+		e.Rule = rule.ID
+	}
+	if err != nil {
+		e.Decision = audit.DecisionDenied
+		e.Reason = err.Error()
+	}
+	e.Caller, _ = r.attribute("metadata.Micro-From-Service")
+	return e
+}
@@ -0,0 +1,159 @@
+package rules
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/metadata"
+)
+
+// now returns the time conditions are evaluated at
+var now = time.Now
+
+// request holds what conditions are evaluated against
+type request struct {
+	ctx context.Context
+	acc *auth.Account
+	res *auth.Resource
+	now time.Time
+}
+
+// holds checks all of the conditions hold for the request
+func (r *request) holds(conds []*auth.Condition) bool {
+	for _, c := range conds {
+		if c == nil || !r.eval(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *request) eval(c *auth.Condition) bool {
+	v, ok := r.attribute(c.Attribute)
+
+	if c.Operator == auth.OperatorExists {
+		return ok && len(v) > 0
+	}
+
+	values := make([]string, 0, len(c.Values))
+	for _, val := range c.Values {
+		// references to other attributes must be set
+		if strings.HasPrefix(val, "$") {
+			ref, ok := r.attribute(strings.TrimPrefix(val, "$"))
+			if !ok {
+				return false
+			}
+			val = ref
+		}
+		values = append(values, val)
+	}
+
+	switch c.Operator {
+	case auth.OperatorEqual:
+		return ok && len(values) == 1 && v == values[0]
+	case auth.OperatorNotEqual:
+		return len(values) == 1 && v != values[0]
+	case auth.OperatorIn:
+		return ok && contains(values, v)
+	case auth.OperatorNotIn:
+		return !contains(values, v)
+	case auth.OperatorPrefix:
+		if !ok {
+			return false
+		}
+		for _, p := range values {
+			if strings.HasPrefix(v, p) {
+				return true
+			}
+		}
+		return false
+	case auth.OperatorBetween:
+		return ok && len(values) == 2 && between(c.Attribute, v, values[0], values[1])
+	}
+
+	// unknown operators never hold
+	return false
+}
+
+// attribute returns the value of an attribute of the request
+func (r *request) attribute(name string) (string, bool) {
+	switch {
+	case strings.HasPrefix(name, "account."):
+		if r.acc == nil {
+			return "", false
+		}
+		switch key := strings.TrimPrefix(name, "account."); key {
+		case "id":
+			return r.acc.ID, true
+		case "type":
+			return r.acc.Type, true
+		case "issuer":
+			return r.acc.Issuer, true
+		default:
+			if !strings.HasPrefix(key, "metadata.") {
+				return "", false
+			}
+			v, ok := r.acc.Metadata[strings.TrimPrefix(key, "metadata.")]
+			return v, ok
+		}
+	case strings.HasPrefix(name, "metadata."):
+		if r.ctx == nil {
+			return "", false
+		}
+		return metadata.Get(r.ctx, strings.TrimPrefix(name, "metadata."))
+	case strings.HasPrefix(name, "resource."):
+		switch name {
+		case "resource.name":
+			return r.res.Name, true
+		case "resource.type":
+			return r.res.Type, true
+		case "resource.endpoint":
+			return r.res.Endpoint, true
+		}
+	case name == "time":
+		return r.now.Format(time.RFC3339), true
+	case name == "time.clock":
+		return r.now.Format("15:04"), true
+	case name == "time.weekday":
+		return r.now.Format("Mon"), true
+	}
+	return "", false
+}
+
+// between checks the value is within the range. Clock ranges can wrap
+// around midnight e.g. 22:00 to 06:00.
+func between(attr, v, from, to string) bool {
+	switch attr {
+	case "time":
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return false
+		}
+		f, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return false
+		}
+		e, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return false
+		}
+		return !t.Before(f) && !t.After(e)
+	case "time.clock":
+		if from <= to {
+			return v >= from && v <= to
+		}
+		return v >= from || v <= to
+	}
+	return v >= from && v <= to
+}
+
+func contains(slice []string, v string) bool {
+	for _, s := range slice {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
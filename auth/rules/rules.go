@@ -6,12 +6,33 @@ import (
 	"strings"
 
 	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/audit"
 )
 
 // Verify an account has access to a resource using the rules provided. If the account does not have
 // access an error will be returned. If there are no rules provided which match the resource, an error
-// will be returned
-func Verify(rules []*auth.Rule, acc *auth.Account, res *auth.Resource) error {
+// will be returned. Rules with conditions only apply when all of them hold, request metadata
+// is read from the context set with auth.VerifyContext. If the context has an auditor the
+// decision and the rule which made it are recorded.
+func Verify(rules []*auth.Rule, acc *auth.Account, res *auth.Resource, opts ...auth.VerifyOption) error {
+	var options auth.VerifyOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	req := &request{ctx: options.Context, acc: acc, res: res, now: now().UTC()}
+
+	rule, err := match(rules, req)
+	if a, ok := audit.FromContext(options.Context); ok {
+		a.Record(req.event(rule, err))
+	}
+	return err
+}
+
+// match returns the rule which decides if the request is allowed and the resulting error, the
+// rule is nil if none of the rules applied
+func match(rules []*auth.Rule, req *request) (*auth.Rule, error) {
+	acc, res := req.acc, req.res
+
 	// the rule is only to be applied if the type matches the resource or is catch-all (*)
 	validTypes := []string{"*", res.Type}
 
@@ -40,6 +61,9 @@ func Verify(rules []*auth.Rule, acc *auth.Account, res *auth.Resource) error {
 		if !include(validEndpoints, rule.Resource.Endpoint) {
 			continue
 		}
+		if !req.holds(rule.Conditions) {
+			continue
+		}
 		filteredRules = append(filteredRules, rule)
 	}
 
@@ -52,9 +76,9 @@ func Verify(rules []*auth.Rule, acc *auth.Account, res *auth.Resource) error {
 	for _, rule := range filteredRules {
 		// a blank scope indicates the rule applies to everyone, even nil accounts
 		if rule.Scope == auth.ScopePublic && rule.Access == auth.AccessDenied {
-			return auth.ErrForbidden
+			return rule, auth.ErrForbidden
 		} else if rule.Scope == auth.ScopePublic && rule.Access == auth.AccessGranted {
-			return nil
+			return rule, nil
 		}
 
 		// all further checks require an account
@@ -64,21 +88,21 @@ func Verify(rules []*auth.Rule, acc *auth.Account, res *auth.Resource) error {
 
 		// this rule applies to any account
 		if rule.Scope == auth.ScopeAccount && rule.Access == auth.AccessDenied {
-			return auth.ErrForbidden
+			return rule, auth.ErrForbidden
 		} else if rule.Scope == auth.ScopeAccount && rule.Access == auth.AccessGranted {
-			return nil
+			return rule, nil
 		}
 
 		// if the account has the necessary scope
-		if include(acc.Scopes, rule.Scope) && rule.Access == auth.AccessDenied {
-			return auth.ErrForbidden
-		} else if include(acc.Scopes, rule.Scope) && rule.Access == auth.AccessGranted {
-			return nil
+		if hasScope(acc.Scopes, rule.Scope) && rule.Access == auth.AccessDenied {
+			return rule, auth.ErrForbidden
+		} else if hasScope(acc.Scopes, rule.Scope) && rule.Access == auth.AccessGranted {
+			return rule, nil
 		}
 	}
 
 	// if no rules matched then return forbidden
-	return auth.ErrForbidden
+	return nil, auth.ErrForbidden
 }
 
 // include is a helper function which checks to see if the slice contains the value. includes is
@@ -91,3 +115,36 @@ func include(slice []string, val string) bool {
 	}
 	return false
 }
+
+// hasScope checks if any of the scopes match the rule scope. A scope ending in /* matches
+// any scope under it, e.g. spiffe://example.org/ns/prod/* matches the identities of all
+// services in the namespace.
+func hasScope(scopes []string, scope string) bool {
+	scope = canonicalScope(scope)
+	var prefix string
+	if strings.HasSuffix(scope, "/*") {
+		prefix = strings.TrimSuffix(scope, "*")
+	}
+	for _, s := range scopes {
+		s = canonicalScope(s)
+		if s == scope || (len(prefix) > 0 && strings.HasPrefix(s, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalScope returns the scope in the case it's compared in. Scopes aren't case
+// sensitive except for the path of a SPIFFE ID, only its trust domain is lowercased.
+func canonicalScope(s string) string {
+	const scheme = "spiffe://"
+	if !strings.HasPrefix(strings.ToLower(s), scheme) {
+		return strings.ToLower(s)
+	}
+	i := strings.Index(s[len(scheme):], "/")
+	if i < 0 {
+		return strings.ToLower(s)
+	}
+	i += len(scheme)
+	return strings.ToLower(s[:i]) + s[i:]
+}
@@ -1,9 +1,13 @@
 package rules
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/audit"
+	"c-z.dev/go-micro/metadata"
 )
 
 func TestVerify(t *testing.T) {
@@ -278,6 +282,64 @@ func TestVerify(t *testing.T) {
 			},
 			Error: auth.ErrForbidden,
 		},
+		{
+			Name:     "IdentityScopePrefix",
+			Resource: srvResource,
+			Account:  &auth.Account{Scopes: []string{"spiffe://example.org/ns/prod/foo"}},
+			Rules: []*auth.Rule{
+				{
+					Scope:    "spiffe://example.org/ns/prod/*",
+					Resource: catchallResource,
+				},
+			},
+		},
+		{
+			Name:     "IdentityScopePrefixInvalid",
+			Resource: srvResource,
+			Account:  &auth.Account{Scopes: []string{"spiffe://example.org/ns/dev/foo"}},
+			Rules: []*auth.Rule{
+				{
+					Scope:    "spiffe://example.org/ns/prod/*",
+					Resource: catchallResource,
+				},
+			},
+			Error: auth.ErrForbidden,
+		},
+		{
+			Name:     "IdentityScopeTrustDomainCase",
+			Resource: srvResource,
+			Account:  &auth.Account{Scopes: []string{"spiffe://Example.ORG/ns/prod/foo"}},
+			Rules: []*auth.Rule{
+				{
+					Scope:    "spiffe://example.org/ns/prod/*",
+					Resource: catchallResource,
+				},
+			},
+		},
+		{
+			Name:     "IdentityScopePathCase",
+			Resource: srvResource,
+			Account:  &auth.Account{Scopes: []string{"spiffe://example.org/NS/prod/foo"}},
+			Rules: []*auth.Rule{
+				{
+					Scope:    "spiffe://example.org/ns/prod/*",
+					Resource: catchallResource,
+				},
+			},
+			Error: auth.ErrForbidden,
+		},
+		{
+			Name:     "IdentityScopeExactPathCase",
+			Resource: srvResource,
+			Account:  &auth.Account{Scopes: []string{"spiffe://example.org/ns/prod/Foo"}},
+			Rules: []*auth.Rule{
+				{
+					Scope:    "spiffe://example.org/ns/prod/foo",
+					Resource: catchallResource,
+				},
+			},
+			Error: auth.ErrForbidden,
+		},
 	}
 
 	for _, tc := range tt {
@@ -288,3 +350,145 @@ func TestVerify(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyConditions(t *testing.T) {
+	now = func() time.Time {
+		return time.Date(2020, 6, 1, 23, 30, 0, 0, time.UTC)
+	}
+	defer func() { now = time.Now }()
+
+	res := &auth.Resource{Type: "service", Name: "go.micro.service.foo", Endpoint: "Foo.Bar"}
+	all := &auth.Resource{Type: "*", Name: "*", Endpoint: "*"}
+	acc := &auth.Account{ID: "john", Metadata: map[string]string{"tenant": "acme"}}
+
+	tenant := []*auth.Condition{{
+		Attribute: "account.metadata.tenant",
+		Operator:  auth.OperatorEqual,
+		Values:    []string{"$metadata.Micro-Tenant"},
+	}}
+
+	tt := []struct {
+		Name       string
+		Conditions []*auth.Condition
+		Metadata   metadata.Metadata
+		Error      error
+	}{
+		{
+			Name:       "TenantMatches",
+			Conditions: tenant,
+			Metadata:   metadata.Metadata{"Micro-Tenant": "acme"},
+		},
+		{
+			Name:       "TenantMismatch",
+			Conditions: tenant,
+			Metadata:   metadata.Metadata{"Micro-Tenant": "other"},
+			Error:      auth.ErrForbidden,
+		},
+		{
+			Name:       "TenantMissing",
+			Conditions: tenant,
+			Error:      auth.ErrForbidden,
+		},
+		{
+			Name: "ClockWindowWraps",
+			Conditions: []*auth.Condition{
+				{Attribute: "time.clock", Operator: auth.OperatorBetween, Values: []string{"22:00", "06:00"}},
+			},
+		},
+		{
+			Name: "OutsideClockWindow",
+			Conditions: []*auth.Condition{
+				{Attribute: "time.clock", Operator: auth.OperatorBetween, Values: []string{"09:00", "17:00"}},
+			},
+			Error: auth.ErrForbidden,
+		},
+		{
+			Name: "Weekday",
+			Conditions: []*auth.Condition{
+				{Attribute: "time.weekday", Operator: auth.OperatorIn, Values: []string{"Sat", "Sun", "Mon"}},
+				{Attribute: "time", Operator: auth.OperatorBetween, Values: []string{"2020-01-01T00:00:00Z", "2021-01-01T00:00:00Z"}},
+			},
+		},
+		{
+			Name: "EndpointPrefix",
+			Conditions: []*auth.Condition{
+				{Attribute: "resource.endpoint", Operator: auth.OperatorPrefix, Values: []string{"Bar."}},
+			},
+			Error: auth.ErrForbidden,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			rules := []*auth.Rule{{Scope: "*", Resource: all, Conditions: tc.Conditions}}
+			ctx := metadata.NewContext(context.Background(), tc.Metadata)
+			if err := Verify(rules, acc, res, auth.VerifyContext(ctx)); err != tc.Error {
+				t.Errorf("Expected %v but got %v", tc.Error, err)
+			}
+		})
+	}
+}
+
+type auditSink struct {
+	events []*audit.Event
+}
+
+func (s *auditSink) Write(e *audit.Event) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *auditSink) String() string {
+	return "test"
+}
+
+func TestVerifyAudit(t *testing.T) {
+	res := &auth.Resource{Type: "service", Name: "go.micro.service.foo", Endpoint: "Foo.Bar"}
+	acc := &auth.Account{ID: "john", Issuer: "micro", Scopes: []string{"user"}}
+	rules := []*auth.Rule{
+		{ID: "admins", Scope: "admin", Resource: res, Access: auth.AccessGranted},
+		{ID: "deny-users", Scope: "user", Resource: res, Access: auth.AccessDenied, Priority: 1},
+		{ID: "public-bar", Scope: auth.ScopePublic, Resource: &auth.Resource{Type: "service", Name: "go.micro.service.bar", Endpoint: "*"}, Access: auth.AccessGranted},
+	}
+
+	sink := &auditSink{}
+	auditor := audit.NewAuditor(audit.WithSink(sink), audit.SampleRate(1))
+	ctx := audit.NewContext(context.Background(), auditor)
+	ctx = metadata.NewContext(ctx, metadata.Metadata{"Micro-From-Service": "go.micro.service.baz"})
+
+	if err := Verify(rules, acc, res, auth.VerifyContext(ctx)); err != auth.ErrForbidden {
+		t.Fatalf("Expected forbidden but got %v", err)
+	}
+	bar := &auth.Resource{Type: "service", Name: "go.micro.service.bar", Endpoint: "Bar.Baz"}
+	if err := Verify(rules, nil, bar, auth.VerifyContext(ctx)); err != nil {
+		t.Fatalf("Expected nil error but got %v", err)
+	}
+	other := &auth.Resource{Type: "service", Name: "go.micro.service.other", Endpoint: "Bar.Baz"}
+	if err := Verify(rules, acc, other, auth.VerifyContext(ctx)); err != auth.ErrForbidden {
+		t.Fatalf("Expected forbidden but got %v", err)
+	}
+
+	auditor.Flush()
+
+	expected := []struct {
+		Account  string
+		Rule     string
+		Decision audit.Decision
+	}{
+		{"john", "deny-users", audit.DecisionDenied},
+		{"", "public-bar", audit.DecisionGranted},
+		{"john", "", audit.DecisionDenied},
+	}
+	if len(sink.events) != len(expected) {
+		t.Fatalf("Expected %v events but got %v", len(expected), len(sink.events))
+	}
+	for i, e := range expected {
+		got := sink.events[i]
+		if got.Account != e.Account || got.Rule != e.Rule || got.Decision != e.Decision {
+			t.Errorf("Expected event %+v but got %+v", e, got)
+		}
+		if got.Caller != "go.micro.service.baz" {
+			t.Errorf("Expected caller go.micro.service.baz but got %v", got.Caller)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"c-z.dev/go-micro/auth"
+)
+
+type jwksKey struct{}
+
+// JWKS sets the url of the JSON Web Key Set of the auth service. Tokens are
+// inspected locally with its keys, picking up keys as they're rotated.
+func JWKS(url string) auth.Option {
+	return func(o *auth.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, jwksKey{}, url)
+	}
+}
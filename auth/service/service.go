@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"c-z.dev/go-micro/auth/rules"
 	pb "c-z.dev/go-micro/auth/service/proto"
 	"c-z.dev/go-micro/auth/token"
+	"c-z.dev/go-micro/auth/token/jwt"
 	"c-z.dev/go-micro/client"
 )
 
@@ -35,6 +37,16 @@ func (s *svc) Init(opts ...auth.Option) {
 
 	s.auth = pb.NewAuthService("go.micro.auth", s.options.Client)
 	s.rules = pb.NewRulesService("go.micro.auth", s.options.Client)
+
+	// with the keys of the service tokens can be inspected locally
+	if c := s.options.Context; c != nil {
+		if url, ok := c.Value(jwksKey{}).(string); ok && len(url) > 0 {
+			s.jwt = jwt.NewTokenProvider(jwt.WithKeySet(jwt.RemoteKeys(url)))
+		}
+	}
+	if s.jwt == nil && len(s.options.PublicKey) > 0 {
+		s.jwt = jwt.NewTokenProvider(token.WithPublicKey(s.options.PublicKey))
+	}
 }
 
 func (s *svc) Options() auth.Options {
@@ -80,6 +92,7 @@ func (s *svc) Grant(rule *auth.Rule) error {
 				Name:     rule.Resource.Name,
 				Endpoint: rule.Resource.Endpoint,
 			},
+			Conditions: serializeConditions(rule.Conditions),
 		},
 	})
 
@@ -129,19 +142,25 @@ func (s *svc) Verify(acc *auth.Account, res *auth.Resource, opts ...auth.VerifyO
 		return err
 	}
 
-	return rules.Verify(rs, acc, res)
+	return rules.Verify(rs, acc, res, opts...)
 }
 
 // Inspect a token
-func (s *svc) Inspect(token string) (*auth.Account, error) {
-	// try to decode JWT locally and fall back to srv if an error occurs
-	if len(strings.Split(token, ".")) == 3 && s.jwt != nil {
-		return s.jwt.Inspect(token)
+func (s *svc) Inspect(t string) (*auth.Account, error) {
+	// try to decode JWT locally and fall back to srv if the key is unknown,
+	// the token may be signed with a key the service has since rotated to
+	if len(strings.Split(t, ".")) == 3 && s.jwt != nil {
+		acc, err := s.jwt.Inspect(t)
+		if err == nil {
+			return acc, nil
+		} else if !errors.Is(err, jwt.ErrKeyNotFound) {
+			return nil, token.ErrInvalidToken
+		}
 	}
 
-	// the token is not a JWT or we do not have the keys to decode it,
+	// the token is not a JWT or we do not have the key to decode it,
 	// fall back to the auth service
-	rsp, err := s.auth.Inspect(context.TODO(), &pb.InspectRequest{Token: token})
+	rsp, err := s.auth.Inspect(context.TODO(), &pb.InspectRequest{Token: t})
 	if err != nil {
 		return nil, err
 	}
@@ -202,7 +221,38 @@ func serializeRule(r *pb.Rule) *auth.Rule {
 			Name:     r.Resource.Name,
 			Endpoint: r.Resource.Endpoint,
 		},
+		Conditions: deserializeConditions(r.Conditions),
+	}
+}
+
+func serializeConditions(cs []*auth.Condition) []*pb.Condition {
+	if len(cs) == 0 {
+		return nil
 	}
+	conds := make([]*pb.Condition, len(cs))
+	for i, c := range cs {
+		conds[i] = &pb.Condition{
+			Attribute: c.Attribute,
+			Operator:  string(c.Operator),
+			Values:    c.Values,
+		}
+	}
+	return conds
+}
+
+func deserializeConditions(cs []*pb.Condition) []*auth.Condition {
+	if len(cs) == 0 {
+		return nil
+	}
+	conds := make([]*auth.Condition, len(cs))
+	for i, c := range cs {
+		conds[i] = &auth.Condition{
+			Attribute: c.Attribute,
+			Operator:  auth.Operator(c.Operator),
+			Values:    c.Values,
+		}
+	}
+	return conds
 }
 
 // NewAuth returns a new instance of the Auth service
@@ -212,9 +262,7 @@ func NewAuth(opts ...auth.Option) auth.Auth {
 		options.Client = client.DefaultClient
 	}
 
-	return &svc{
-		auth:    pb.NewAuthService("go.micro.auth", options.Client),
-		rules:   pb.NewRulesService("go.micro.auth", options.Client),
-		options: options,
-	}
+	s := &svc{options: options}
+	s.Init()
+	return s
 }
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"reflect"
+	"testing"
+
+	"c-z.dev/go-micro/auth"
+	pb "c-z.dev/go-micro/auth/service/proto"
+	"c-z.dev/go-micro/auth/token"
+	"c-z.dev/go-micro/auth/token/jwt"
+	"c-z.dev/go-micro/client"
+	"c-z.dev/go-micro/errors"
+)
+
+func TestRuleConditions(t *testing.T) {
+	rule := &auth.Rule{
+		ID:       "tenant",
+		Scope:    "user",
+		Access:   auth.AccessGranted,
+		Priority: 1,
+		Resource: &auth.Resource{Type: "service", Name: "go.micro.notes", Endpoint: "*"},
+		Conditions: []*auth.Condition{
+			{Attribute: "account.metadata.tenant", Operator: auth.OperatorEqual, Values: []string{"$metadata.X-Tenant"}},
+			{Attribute: "time.clock", Operator: auth.OperatorBetween, Values: []string{"09:00", "17:00"}},
+		},
+	}
+
+	r := &pb.Rule{
+		Id:         rule.ID,
+		Scope:      rule.Scope,
+		Access:     pb.Access_GRANTED,
+		Priority:   rule.Priority,
+		Resource:   &pb.Resource{Type: "service", Name: "go.micro.notes", Endpoint: "*"},
+		Conditions: serializeConditions(rule.Conditions),
+	}
+	if got := serializeRule(r); !reflect.DeepEqual(got, rule) {
+		t.Fatalf("Expected %+v got %+v", rule, got)
+	}
+
+	// rules without conditions don't gain an empty list
+	r.Conditions = serializeConditions(nil)
+	if got := serializeRule(r); got.Conditions != nil {
+		t.Fatalf("Expected no conditions got %v", got.Conditions)
+	}
+}
+
+// testAuthService inspects the tokens signed by its provider
+type testAuthService struct {
+	pb.AuthService
+	jwt   token.Provider
+	calls int
+}
+
+func (s *testAuthService) Inspect(ctx context.Context, req *pb.InspectRequest, opts ...client.CallOption) (*pb.InspectResponse, error) {
+	s.calls++
+	acc, err := s.jwt.Inspect(req.Token)
+	if err != nil {
+		return nil, errors.Unauthorized("go.micro.auth", err.Error())
+	}
+	return &pb.InspectResponse{Account: &pb.Account{Id: acc.ID, Scopes: acc.Scopes}}, nil
+}
+
+func testKey(t *testing.T) (string, string) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, _ := x509.MarshalECPrivateKey(priv)
+	pubDER, _ := x509.MarshalPKIXPublicKey(priv.Public())
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})),
+		base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privDER}))
+}
+
+func TestInspectRotatedKey(t *testing.T) {
+	oldPub, oldPriv := testKey(t)
+	newPub, newPriv := testKey(t)
+
+	old := jwt.NewTokenProvider(token.WithPublicKey(oldPub), token.WithPrivateKey(oldPriv))
+	rotated := jwt.NewTokenProvider(token.WithPublicKey(newPub), token.WithPrivateKey(newPriv))
+
+	a := NewAuth(auth.PublicKey(oldPub)).(*svc)
+	srv := &testAuthService{jwt: rotated}
+	a.auth = srv
+
+	// tokens signed with the configured key are inspected locally
+	tok, err := old.Generate(&auth.Account{ID: "john"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc, err := a.Inspect(tok.Token); err != nil || acc.ID != "john" {
+		t.Fatalf("Expected john got %v %v", acc, err)
+	}
+
+	// and rejected locally when invalid
+	if _, err := a.Inspect(tok.Token[:len(tok.Token)-4] + "AAAA"); err != token.ErrInvalidToken {
+		t.Fatalf("Expected invalid token error got %v", err)
+	}
+	if srv.calls != 0 {
+		t.Fatalf("Expected no calls to the service got %v", srv.calls)
+	}
+
+	// tokens signed with a key the service rotated to are inspected by the service
+	tok, err = rotated.Generate(&auth.Account{ID: "jane"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc, err := a.Inspect(tok.Token); err != nil || acc.ID != "jane" {
+		t.Fatalf("Expected jane got %v %v", acc, err)
+	}
+	if srv.calls != 1 {
+		t.Fatalf("Expected 1 call to the service got %v", srv.calls)
+	}
+
+	// and still rejected by it when invalid
+	if _, err := a.Inspect(tok.Token[:len(tok.Token)-4] + "AAAA"); err == nil {
+		t.Fatal("Expected invalid token error")
+	}
+}
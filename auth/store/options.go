@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/token"
+	msync "c-z.dev/go-micro/sync"
+)
+
+type tokenProviderKey struct{}
+type refreshExpiryKey struct{}
+type syncKey struct{}
+
+// TokenProvider sets the provider used to generate and inspect access
+// tokens, it defaults to an opaque token held in the auth store
+func TokenProvider(p token.Provider) auth.Option {
+	return setOption(tokenProviderKey{}, p)
+}
+
+// RefreshExpiry sets how long refresh tokens are valid for
+func RefreshExpiry(d time.Duration) auth.Option {
+	return setOption(refreshExpiryKey{}, d)
+}
+
+// Sync sets the distributed lock refresh tokens are used under, so a refresh
+// token is only used once by the replicas sharing the store
+func Sync(s msync.Sync) auth.Option {
+	return setOption(syncKey{}, s)
+}
+
+func setOption(k, v interface{}) auth.Option {
+	return func(o *auth.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
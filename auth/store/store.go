@@ -0,0 +1,561 @@
+// Package store is an auth implementation which persists accounts, refresh
+// tokens and rules in a store so they're shared between replicas
+package store
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/rules"
+	"c-z.dev/go-micro/auth/token"
+	"c-z.dev/go-micro/auth/token/basic"
+	"c-z.dev/go-micro/store"
+	msync "c-z.dev/go-micro/sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// DefaultRefreshExpiry is how long refresh tokens are valid for
+	DefaultRefreshExpiry = time.Hour * 24 * 30
+
+	// ErrInvalidCredentials is returned when the account id or secret is wrong
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrRevokedToken is returned when inspecting a revoked token
+	ErrRevokedToken = errors.New("token has been revoked")
+	// ErrAccountExists is returned when federating an account which belongs to another provider
+	ErrAccountExists = errors.New("account exists with another provider")
+)
+
+const (
+	accountPrefix = "account/"
+	refreshPrefix = "refresh/"
+	rulePrefix    = "rule/"
+	revokedPrefix = "revoked/"
+)
+
+// Revoker is implemented by the store auth to revoke tokens before they expire
+type Revoker interface {
+	// RevokeToken revokes an access or refresh token
+	RevokeToken(token string) error
+	// RevokeAccount deletes the account and its refresh tokens
+	RevokeAccount(id string) error
+}
+
+// Federator is implemented by the store auth to sign in accounts of external identity providers
+type Federator interface {
+	// Federate issues a token to the account of the provider, which is created the
+	// first time it signs in and updated after. Accounts of other providers are
+	// never replaced and the other sessions of the account remain valid.
+	Federate(id string, opts ...auth.GenerateOption) (*auth.Token, error)
+}
+
+// Reader is implemented by the store auth to read accounts
+type Reader interface {
+	// ReadAccount returns the account without its secret, store.ErrNotFound
+	// is returned if the account doesn't exist
+	ReadAccount(id string) (*auth.Account, error)
+}
+
+// account is the stored form of an account
+type account struct {
+	auth.Account
+	// Provider of the account
+	Provider string `json:"provider"`
+	// SecretHash is a bcrypt hash of the secret
+	SecretHash []byte `json:"secret_hash"`
+	// Refresh tokens issued to the account, hashed
+	Refresh []string `json:"refresh"`
+}
+
+// refresh is the stored form of a refresh token
+type refresh struct {
+	Account string    `json:"account"`
+	Expiry  time.Time `json:"expiry"`
+}
+
+type storeAuth struct {
+	sync.RWMutex
+	options       auth.Options
+	store         store.Store
+	tokens        token.Provider
+	refreshExpiry time.Duration
+
+	// serialises the use of refresh tokens so each is only used once,
+	// the lock is used across replicas when set
+	refreshMtx  sync.Mutex
+	refreshLock msync.Sync
+}
+
+// NewAuth returns an auth backed by the store set with auth.Store
+func NewAuth(opts ...auth.Option) auth.Auth {
+	s := &storeAuth{}
+	s.Init(opts...)
+	return s
+}
+
+func (s *storeAuth) String() string {
+	return "store"
+}
+
+func (s *storeAuth) Init(opts ...auth.Option) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, o := range opts {
+		o(&s.options)
+	}
+
+	s.store = s.options.Store
+	if s.store == nil {
+		s.store = store.DefaultStore
+	}
+
+	s.refreshExpiry = DefaultRefreshExpiry
+	s.tokens = nil
+	s.refreshLock = nil
+	if c := s.options.Context; c != nil {
+		if d, ok := c.Value(refreshExpiryKey{}).(time.Duration); ok && d > 0 {
+			s.refreshExpiry = d
+		}
+		if p, ok := c.Value(tokenProviderKey{}).(token.Provider); ok && p != nil {
+			s.tokens = p
+		}
+		if l, ok := c.Value(syncKey{}).(msync.Sync); ok {
+			s.refreshLock = l
+		}
+	}
+	if s.tokens == nil {
+		s.tokens = basic.NewTokenProvider(token.WithStore(s.store))
+	}
+}
+
+func (s *storeAuth) Options() auth.Options {
+	s.RLock()
+	defer s.RUnlock()
+	return s.options
+}
+
+// key returns the store key in the auth namespace
+func (s *storeAuth) key(prefix, id string) string {
+	return "auth/" + s.options.Namespace + "/" + prefix + id
+}
+
+// Generate a new account, an existing account with the same id is replaced.
+// The secret is only returned here, it's stored as a hash.
+func (s *storeAuth) Generate(id string, opts ...auth.GenerateOption) (*auth.Account, error) {
+	options := auth.NewGenerateOptions(opts...)
+
+	s.RLock()
+	defer s.RUnlock()
+
+	secret := options.Secret
+	if len(secret) == 0 {
+		secret = uuid.New().String()
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := &account{
+		Account: auth.Account{
+			ID:       id,
+			Type:     options.Type,
+			Issuer:   s.options.Namespace,
+			Metadata: options.Metadata,
+			Scopes:   options.Scopes,
+		},
+		Provider:   options.Provider,
+		SecretHash: hash,
+	}
+
+	// tokens issued to a replaced account are no longer valid
+	if old, err := s.readAccount(id); err == nil {
+		s.deleteRefresh(old.Refresh...)
+	}
+
+	if err := s.writeAccount(acc); err != nil {
+		return nil, err
+	}
+
+	a := acc.Account
+	a.Secret = secret
+	return &a, nil
+}
+
+// Grant access to a resource
+func (s *storeAuth) Grant(rule *auth.Rule) error {
+	if rule == nil || rule.Resource == nil || len(rule.ID) == 0 {
+		return errors.New("rule requires an id and resource")
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	b, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+	return s.store.Write(&store.Record{Key: s.key(rulePrefix, rule.ID), Value: b})
+}
+
+// Revoke access to a resource
+func (s *storeAuth) Revoke(rule *auth.Rule) error {
+	if rule == nil || len(rule.ID) == 0 {
+		return errors.New("rule requires an id")
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	err := s.store.Delete(s.key(rulePrefix, rule.ID))
+	if err == store.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// Rules used to verify requests
+func (s *storeAuth) Rules(opts ...auth.RulesOption) ([]*auth.Rule, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	recs, err := s.store.Read(s.key(rulePrefix, ""), store.ReadPrefix())
+	if err == store.ErrNotFound {
+		return []*auth.Rule{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*auth.Rule, 0, len(recs))
+	for _, r := range recs {
+		var rule *auth.Rule
+		if err := json.Unmarshal(r.Value, &rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Verify an account has access to a resource using the rules
+func (s *storeAuth) Verify(acc *auth.Account, res *auth.Resource, opts ...auth.VerifyOption) error {
+	var options auth.VerifyOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	rs, err := s.Rules(auth.RulesContext(options.Context))
+	if err != nil {
+		return err
+	}
+
+	return rules.Verify(rs, acc, res, opts...)
+}
+
+// Inspect an access token
+func (s *storeAuth) Inspect(t string) (*auth.Account, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.revoked(t) {
+		return nil, ErrRevokedToken
+	}
+
+	acc, err := s.tokens.Inspect(t)
+	if errors.Is(err, token.ErrInvalidToken) || err == store.ErrNotFound {
+		return nil, auth.ErrInvalidToken
+	} else if err != nil {
+		return nil, err
+	}
+
+	// tokens of deleted accounts are no longer valid
+	if _, err := s.readAccount(acc.ID); err == store.ErrNotFound {
+		return nil, auth.ErrInvalidToken
+	} else if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// Token generated using credentials or a refresh token. Refresh tokens are
+// single use, a new one is returned with each token.
+func (s *storeAuth) Token(opts ...auth.TokenOption) (*auth.Token, error) {
+	options := auth.NewTokenOptions(opts...)
+
+	s.RLock()
+	defer s.RUnlock()
+
+	var acc *account
+
+	if len(options.RefreshToken) > 0 {
+		id, err := s.useRefresh(options.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		acc, err = s.readAccount(id)
+		if err == store.ErrNotFound {
+			return nil, auth.ErrInvalidToken
+		} else if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		acc, err = s.readAccount(options.ID)
+		if err == store.ErrNotFound {
+			return nil, ErrInvalidCredentials
+		} else if err != nil {
+			return nil, err
+		}
+		if err := bcrypt.CompareHashAndPassword(acc.SecretHash, []byte(options.Secret)); err != nil {
+			return nil, ErrInvalidCredentials
+		}
+	}
+
+	return s.token(acc, options.Expiry)
+}
+
+// Federate issues a token to the account of a provider, federated accounts have
+// no secret so they can only sign in through the provider
+func (s *storeAuth) Federate(id string, opts ...auth.GenerateOption) (*auth.Token, error) {
+	options := auth.NewGenerateOptions(opts...)
+	if len(id) == 0 || len(options.Provider) == 0 {
+		return nil, errors.New("federated account requires an id and provider")
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	acc, err := s.readAccount(id)
+	if err == store.ErrNotFound {
+		acc = &account{
+			Account:  auth.Account{ID: id, Issuer: s.options.Namespace},
+			Provider: options.Provider,
+		}
+	} else if err != nil {
+		return nil, err
+	} else if acc.Provider != options.Provider {
+		return nil, ErrAccountExists
+	}
+
+	// the provider is the source of truth for the account
+	acc.Type = options.Type
+	acc.Scopes = options.Scopes
+	acc.Metadata = options.Metadata
+
+	return s.token(acc, auth.NewTokenOptions().Expiry)
+}
+
+// token issues an access and refresh token to the account
+func (s *storeAuth) token(acc *account, expiry time.Duration) (*auth.Token, error) {
+	tok, err := s.tokens.Generate(&acc.Account, token.WithExpiry(expiry))
+	if err != nil {
+		return nil, err
+	}
+
+	rt, err := s.issueRefresh(acc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.Token{
+		AccessToken:  tok.Token,
+		RefreshToken: rt,
+		Created:      tok.Created,
+		Expiry:       tok.Expiry,
+	}, nil
+}
+
+// RevokeToken adds the token to the revocation list until it would have expired
+func (s *storeAuth) RevokeToken(t string) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	// refresh tokens are deleted
+	h := hash(t)
+	if recs, err := s.store.Read(s.key(refreshPrefix, h)); err == nil {
+		var r *refresh
+		if err := json.Unmarshal(recs[0].Value, &r); err == nil {
+			s.deleteRefresh(h)
+			if acc, err := s.readAccount(r.Account); err == nil {
+				acc.Refresh = remove(acc.Refresh, h)
+				s.writeAccount(acc)
+			}
+			return nil
+		}
+	}
+
+	if _, err := s.tokens.Inspect(t); err != nil {
+		return auth.ErrInvalidToken
+	}
+
+	return s.store.Write(&store.Record{
+		Key:    s.key(revokedPrefix, h),
+		Value:  []byte(time.Now().Format(time.RFC3339)),
+		Expiry: s.refreshExpiry,
+	})
+}
+
+// RevokeAccount deletes the account and its refresh tokens
+func (s *storeAuth) RevokeAccount(id string) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	acc, err := s.readAccount(id)
+	if err == store.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	s.deleteRefresh(acc.Refresh...)
+	return s.store.Delete(s.key(accountPrefix, id))
+}
+
+// ReadAccount returns the account without its secret
+func (s *storeAuth) ReadAccount(id string) (*auth.Account, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	acc, err := s.readAccount(id)
+	if err != nil {
+		return nil, err
+	}
+	a := acc.Account
+	return &a, nil
+}
+
+func (s *storeAuth) readAccount(id string) (*account, error) {
+	if len(id) == 0 {
+		return nil, store.ErrNotFound
+	}
+	recs, err := s.store.Read(s.key(accountPrefix, id))
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, store.ErrNotFound
+	}
+	var acc *account
+	if err := json.Unmarshal(recs[0].Value, &acc); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+func (s *storeAuth) writeAccount(acc *account) error {
+	b, err := json.Marshal(acc)
+	if err != nil {
+		return err
+	}
+	return s.store.Write(&store.Record{Key: s.key(accountPrefix, acc.ID), Value: b})
+}
+
+// issueRefresh creates a refresh token for the account, only its hash is stored
+func (s *storeAuth) issueRefresh(acc *account) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	t := base64.RawURLEncoding.EncodeToString(b)
+	h := hash(t)
+
+	v, err := json.Marshal(&refresh{Account: acc.ID, Expiry: time.Now().Add(s.refreshExpiry)})
+	if err != nil {
+		return "", err
+	}
+	if err := s.store.Write(&store.Record{
+		Key:    s.key(refreshPrefix, h),
+		Value:  v,
+		Expiry: s.refreshExpiry,
+	}); err != nil {
+		return "", err
+	}
+
+	// drop any refresh tokens which have since expired
+	live := []string{h}
+	for _, r := range acc.Refresh {
+		if _, err := s.store.Read(s.key(refreshPrefix, r)); err == nil {
+			live = append(live, r)
+		}
+	}
+	acc.Refresh = live
+	if err := s.writeAccount(acc); err != nil {
+		return "", err
+	}
+
+	return t, nil
+}
+
+// useRefresh consumes a refresh token and returns the account id it was issued to.
+// The store can't delete conditionally so a token is only guaranteed to be used
+// once within the process, or across the replicas if the Sync option is set.
+func (s *storeAuth) useRefresh(t string) (string, error) {
+	h := hash(t)
+
+	if s.refreshLock != nil {
+		id := s.key(refreshPrefix, h)
+		if err := s.refreshLock.Lock(id, msync.LockTTL(time.Second*10), msync.LockWait(time.Second*5)); err != nil {
+			return "", err
+		}
+		defer s.refreshLock.Unlock(id)
+	} else {
+		s.refreshMtx.Lock()
+		defer s.refreshMtx.Unlock()
+	}
+
+	recs, err := s.store.Read(s.key(refreshPrefix, h))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return "", auth.ErrInvalidToken
+	} else if err != nil {
+		return "", err
+	}
+
+	var r *refresh
+	if err := json.Unmarshal(recs[0].Value, &r); err != nil {
+		return "", err
+	}
+	if err := s.store.Delete(s.key(refreshPrefix, h)); err != nil {
+		return "", err
+	}
+
+	if time.Now().After(r.Expiry) {
+		return "", auth.ErrInvalidToken
+	}
+	return r.Account, nil
+}
+
+func (s *storeAuth) deleteRefresh(hashes ...string) {
+	for _, h := range hashes {
+		s.store.Delete(s.key(refreshPrefix, h))
+	}
+}
+
+func (s *storeAuth) revoked(t string) bool {
+	_, err := s.store.Read(s.key(revokedPrefix, hash(t)))
+	return err == nil
+}
+
+// hash a token so it isn't stored in plain text
+func hash(t string) string {
+	h := sha256.Sum256([]byte(t))
+	return hex.EncodeToString(h[:])
+}
+
+func remove(slice []string, v string) []string {
+	out := slice[:0]
+	for _, s := range slice {
+		if s != v {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,225 @@
+package store
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/store"
+	"c-z.dev/go-micro/store/memory"
+	msync "c-z.dev/go-micro/sync/memory"
+)
+
+func TestStoreAuth(t *testing.T) {
+	st := memory.NewStore()
+	a := NewAuth(auth.Store(st), auth.Namespace("go.micro"))
+
+	acc, err := a.Generate("john", auth.WithSecret("password"), auth.WithScopes("admin"), auth.WithType("user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Secret != "password" || acc.Issuer != "go.micro" {
+		t.Fatalf("Unexpected account %+v", acc)
+	}
+
+	// the secret is only stored as a hash
+	recs, err := st.Read("auth/go.micro/account/john")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recs[0].Value) == "" || bytes.Contains(recs[0].Value, []byte("password")) {
+		t.Fatal("Expected the secret to be hashed")
+	}
+
+	if _, err := a.Token(auth.WithCredentials("john", "wrong")); err != ErrInvalidCredentials {
+		t.Fatalf("Expected invalid credentials got %v", err)
+	}
+
+	tok, err := a.Token(auth.WithCredentials("john", "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another instance sharing the store can inspect the token
+	other := NewAuth(auth.Store(st), auth.Namespace("go.micro"))
+	insp, err := other.Inspect(tok.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if insp.ID != "john" || len(insp.Scopes) != 1 || insp.Secret != "" {
+		t.Fatalf("Unexpected account %+v", insp)
+	}
+
+	// refresh tokens are single use
+	tok2, err := a.Token(auth.WithToken(tok.RefreshToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Token(auth.WithToken(tok.RefreshToken)); err != auth.ErrInvalidToken {
+		t.Fatalf("Expected invalid token got %v", err)
+	}
+
+	// revoked tokens fail inspection
+	r := a.(Revoker)
+	if err := r.RevokeToken(tok2.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Inspect(tok2.AccessToken); err != ErrRevokedToken {
+		t.Fatalf("Expected revoked token got %v", err)
+	}
+	if err := r.RevokeToken(tok2.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Token(auth.WithToken(tok2.RefreshToken)); err != auth.ErrInvalidToken {
+		t.Fatalf("Expected invalid token got %v", err)
+	}
+
+	// rules are shared too
+	res := &auth.Resource{Type: "service", Name: "go.micro.foo", Endpoint: "Foo.Bar"}
+	if err := other.Verify(insp, res); err != auth.ErrForbidden {
+		t.Fatalf("Expected forbidden got %v", err)
+	}
+	if err := a.Grant(&auth.Rule{ID: "admin", Scope: "admin", Resource: &auth.Resource{Type: "*", Name: "*", Endpoint: "*"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Verify(insp, res); err != nil {
+		t.Fatalf("Expected access got %v", err)
+	}
+	if err := a.Revoke(&auth.Rule{ID: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Verify(insp, res); err != auth.ErrForbidden {
+		t.Fatalf("Expected forbidden got %v", err)
+	}
+
+	// deleting the account invalidates its tokens
+	tok3, err := a.Token(auth.WithCredentials("john", "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RevokeAccount("john"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Inspect(tok3.AccessToken); err != auth.ErrInvalidToken {
+		t.Fatalf("Expected invalid token got %v", err)
+	}
+	if _, err := a.Token(auth.WithToken(tok3.RefreshToken)); err != auth.ErrInvalidToken {
+		t.Fatalf("Expected invalid token got %v", err)
+	}
+}
+
+func TestStoreAuthFederate(t *testing.T) {
+	a := NewAuth(auth.Store(memory.NewStore()), auth.Namespace("go.micro"))
+	f := a.(Federator)
+
+	tok, err := f.Federate("https://issuer|jane", auth.WithProvider("oidc"), auth.WithScopes("user"), auth.WithType("user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc, err := a.Inspect(tok.AccessToken)
+	if err != nil || acc.ID != "https://issuer|jane" || acc.Scopes[0] != "user" {
+		t.Fatalf("Unexpected account %+v %v", acc, err)
+	}
+
+	// signing in again updates the account and keeps the other sessions
+	second, err := f.Federate("https://issuer|jane", auth.WithProvider("oidc"), auth.WithScopes("admin"), auth.WithType("user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc, err := a.Inspect(second.AccessToken); err != nil || acc.Scopes[0] != "admin" {
+		t.Fatalf("Expected updated scopes got %+v %v", acc, err)
+	}
+	if _, err := a.Token(auth.WithToken(tok.RefreshToken)); err != nil {
+		t.Fatalf("Expected the first session to be valid got %v", err)
+	}
+
+	// federated accounts have no secret
+	if _, err := a.Token(auth.WithCredentials("https://issuer|jane", "")); err != ErrInvalidCredentials {
+		t.Fatalf("Expected invalid credentials got %v", err)
+	}
+
+	// accounts of other providers aren't replaced
+	if _, err := a.Generate("john", auth.WithSecret("password")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Federate("john", auth.WithProvider("oidc")); err != ErrAccountExists {
+		t.Fatalf("Expected account exists got %v", err)
+	}
+}
+
+// slowStore delays reads so concurrent requests overlap
+type slowStore struct {
+	store.Store
+}
+
+func (s *slowStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	recs, err := s.Store.Read(key, opts...)
+	time.Sleep(time.Millisecond * 10)
+	return recs, err
+}
+
+func TestStoreAuthRefreshOnce(t *testing.T) {
+	st := &slowStore{memory.NewStore()}
+	a := NewAuth(auth.Store(st), auth.Namespace("go.micro"))
+	if _, err := a.Generate("john", auth.WithSecret("password")); err != nil {
+		t.Fatal(err)
+	}
+	tok, err := a.Token(auth.WithCredentials("john", "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a refresh token used concurrently only issues one token
+	var used int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := a.Token(auth.WithToken(tok.RefreshToken)); err == nil {
+				atomic.AddInt32(&used, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if used != 1 {
+		t.Fatalf("Expected the refresh token to be used once but was used %d times", used)
+	}
+
+	// replicas sharing the store use refresh tokens under the distributed lock
+	lock := msync.NewSync()
+	replicas := []auth.Auth{
+		NewAuth(auth.Store(st), auth.Namespace("go.micro"), Sync(lock)),
+		NewAuth(auth.Store(st), auth.Namespace("go.micro"), Sync(lock)),
+	}
+	tok, err = a.Token(auth.WithCredentials("john", "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	used = 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(r auth.Auth) {
+			defer wg.Done()
+			if _, err := r.Token(auth.WithToken(tok.RefreshToken)); err == nil {
+				atomic.AddInt32(&used, 1)
+			}
+		}(replicas[i%2])
+	}
+	wg.Wait()
+
+	if used != 1 {
+		t.Fatalf("Expected the refresh token to be used once by the replicas but was used %d times", used)
+	}
+}
+
+func TestStoreAuthRevokeNil(t *testing.T) {
+	a := NewAuth(auth.Store(memory.NewStore()), auth.Namespace("go.micro"))
+	if err := a.Revoke(nil); err == nil {
+		t.Fatal("Expected an error revoking a nil rule")
+	}
+}
@@ -0,0 +1,255 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JWK is a public key in a JSON Web Key Set
+type JWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// NewJWK encodes a public key
+func NewJWK(kid string, pub crypto.PublicKey) (*JWK, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			Kid: kid,
+			Kty: "RSA",
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return &JWK{
+			Kid: kid,
+			Kty: "EC",
+			Alg: ecAlg(k),
+			Use: "sig",
+			Crv: k.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(pad(k.X.Bytes(), size)),
+			Y:   base64.RawURLEncoding.EncodeToString(pad(k.Y.Bytes(), size)),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported public key %T", pub)
+}
+
+// PublicKey decodes the key
+func (j *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("invalid EC key")
+		}
+		return pub, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", j.Kty)
+}
+
+// NewJWKS returns the public keys of the key set
+func NewJWKS(ks KeySet) (*JWKS, error) {
+	keys, err := ks.Keys()
+	if err != nil {
+		return nil, err
+	}
+	set := &JWKS{Keys: make([]*JWK, 0, len(keys))}
+	for _, k := range keys {
+		jwk, err := NewJWK(k.ID, k.Public)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// NewHandler serves the public keys of the key set as a JWKS document,
+// e.g. mounted at /.well-known/jwks.json
+func NewHandler(ks KeySet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set, err := NewJWKS(ks)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "max-age=300")
+		json.NewEncoder(w).Encode(set)
+	})
+}
+
+type remoteKeys struct {
+	client *http.Client
+	url    string
+
+	sync.Mutex
+	keys    []*Key
+	fetched time.Time
+}
+
+// RemoteKeys returns a key set which verifies tokens with the keys served
+// as a JWKS document at the url. Keys are refetched when a token has an
+// unknown key id so rotated keys are picked up.
+func RemoteKeys(url string) KeySet {
+	return &remoteKeys{
+		client: &http.Client{Timeout: time.Second * 10},
+		url:    url,
+	}
+}
+
+func (r *remoteKeys) Signing() (*Key, error) {
+	return nil, errors.New("remote keys can only verify")
+}
+
+func (r *remoteKeys) Verifying(id string) (*Key, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if time.Since(r.fetched) > DefaultRetention {
+		if err := r.fetch(); err != nil {
+			return nil, err
+		}
+	}
+	if key := r.find(id); key != nil {
+		return key, nil
+	}
+
+	// limit how often unknown key ids cause a fetch
+	if time.Since(r.fetched) > refresh {
+		if err := r.fetch(); err != nil {
+			return nil, err
+		}
+		if key := r.find(id); key != nil {
+			return key, nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (r *remoteKeys) Keys() ([]*Key, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if len(r.keys) == 0 {
+		if err := r.fetch(); err != nil {
+			return nil, err
+		}
+	}
+	return append([]*Key{}, r.keys...), nil
+}
+
+func (r *remoteKeys) Rotate() error {
+	return errors.New("remote keys can't be rotated")
+}
+
+func (r *remoteKeys) find(id string) *Key {
+	for _, k := range r.keys {
+		if k.ID == id {
+			return k
+		}
+	}
+	return nil
+}
+
+func (r *remoteKeys) fetch() error {
+	rsp, err := r.client.Get(r.url)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", r.url, rsp.Status)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(rsp.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make([]*Key, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys = append(keys, &Key{ID: jwk.Kid, Public: pub})
+	}
+	r.keys = keys
+	r.fetched = time.Now()
+	return nil
+}
+
+func ecAlg(k *ecdsa.PublicKey) string {
+	switch k.Curve.Params().BitSize {
+	case 384:
+		return "ES384"
+	case 521:
+		return "ES512"
+	}
+	return "ES256"
+}
+
+func pad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
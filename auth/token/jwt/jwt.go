@@ -0,0 +1,269 @@
+// Package jwt is a token provider which issues JWTs signed with a key from a
+// key set. Each token carries the id of its signing key so keys can be
+// rotated while tokens signed with previous keys remain valid.
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/token"
+)
+
+// unknownKey is the invalid token error returned for a token signed with
+// an unknown key, it matches both token.ErrInvalidToken and ErrKeyNotFound
+// so callers can verify the token elsewhere
+type unknownKey struct{}
+
+func (unknownKey) Error() string {
+	return token.ErrInvalidToken.Error()
+}
+
+func (unknownKey) Is(err error) bool {
+	return err == token.ErrInvalidToken || err == ErrKeyNotFound
+}
+
+// JWT implementation of token provider
+type JWT struct {
+	opts token.Options
+	keys KeySet
+	err  error
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid,omitempty"`
+}
+
+type claims struct {
+	Subject   string            `json:"sub"`
+	Issuer    string            `json:"iss,omitempty"`
+	IssuedAt  int64             `json:"iat"`
+	NotBefore int64             `json:"nbf"`
+	Expiry    int64             `json:"exp"`
+	Type      string            `json:"type,omitempty"`
+	Scopes    []string          `json:"scopes,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// NewTokenProvider returns a JWT provider. Keys are set with WithKeySet, or
+// with token.WithPublicKey and token.WithPrivateKey for a single key pair.
+func NewTokenProvider(opts ...token.Option) token.Provider {
+	options := token.NewOptions(opts...)
+
+	j := &JWT{opts: options}
+	if options.Context != nil {
+		if ks, ok := options.Context.Value(keySetKey{}).(KeySet); ok && ks != nil {
+			j.keys = ks
+		}
+	}
+	if j.keys == nil {
+		j.keys, j.err = StaticKeys(options.PublicKey, options.PrivateKey)
+	}
+	return j
+}
+
+// Generate a new JWT
+func (j *JWT) Generate(acc *auth.Account, opts ...token.GenerateOption) (*token.Token, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	options := token.NewGenerateOptions(opts...)
+
+	key, err := j.keys.Signing()
+	if err != nil {
+		return nil, err
+	}
+
+	alg, err := algorithm(key.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	exp := now.Add(options.Expiry)
+
+	h, err := json.Marshal(&header{Alg: alg, Typ: "JWT", Kid: key.ID})
+	if err != nil {
+		return nil, err
+	}
+	c, err := json.Marshal(&claims{
+		Subject:   acc.ID,
+		Issuer:    acc.Issuer,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		Expiry:    exp.Unix(),
+		Type:      acc.Type,
+		Scopes:    acc.Scopes,
+		Metadata:  acc.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	sig, err := sign(key.Private, []byte(signed))
+	if err != nil {
+		return nil, token.ErrEncodingToken
+	}
+
+	return &token.Token{
+		Token:   signed + "." + base64.RawURLEncoding.EncodeToString(sig),
+		Created: now,
+		Expiry:  exp,
+	}, nil
+}
+
+// Inspect a JWT
+func (j *JWT) Inspect(t string) (*auth.Account, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+
+	parts := strings.Split(t, ".")
+	if len(parts) != 3 {
+		return nil, token.ErrInvalidToken
+	}
+
+	var h header
+	if err := decode(parts[0], &h); err != nil {
+		return nil, token.ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, token.ErrInvalidToken
+	}
+
+	key, err := j.keys.Verifying(h.Kid)
+	if err == ErrKeyNotFound {
+		return nil, unknownKey{}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := verify(h.Alg, key.Public, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, token.ErrInvalidToken
+	}
+
+	var c claims
+	if err := decode(parts[1], &c); err != nil {
+		return nil, token.ErrInvalidToken
+	}
+	now := time.Now().Unix()
+	if c.Expiry < now || c.NotBefore > now {
+		return nil, token.ErrInvalidToken
+	}
+
+	return &auth.Account{
+		ID:       c.Subject,
+		Issuer:   c.Issuer,
+		Type:     c.Type,
+		Scopes:   c.Scopes,
+		Metadata: c.Metadata,
+	}, nil
+}
+
+// String returns JWT
+func (j *JWT) String() string {
+	return "jwt"
+}
+
+func algorithm(k crypto.Signer) (string, error) {
+	switch k := k.(type) {
+	case *rsa.PrivateKey:
+		return "RS256", nil
+	case *ecdsa.PrivateKey:
+		return ecAlg(&k.PublicKey), nil
+	}
+	return "", fmt.Errorf("unsupported signing key %T", k)
+}
+
+func digest(alg string, b []byte) (crypto.Hash, []byte, error) {
+	switch alg {
+	case "RS256", "ES256":
+		d := sha256.Sum256(b)
+		return crypto.SHA256, d[:], nil
+	case "RS384", "ES384":
+		d := sha512.Sum384(b)
+		return crypto.SHA384, d[:], nil
+	case "RS512", "ES512":
+		d := sha512.Sum512(b)
+		return crypto.SHA512, d[:], nil
+	}
+	return 0, nil, fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+func sign(k crypto.Signer, b []byte) ([]byte, error) {
+	alg, err := algorithm(k)
+	if err != nil {
+		return nil, err
+	}
+	hash, d, err := digest(alg, b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := k.(type) {
+	case *rsa.PrivateKey:
+		return rsa.SignPKCS1v15(rand.Reader, k, hash, d)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, d)
+		if err != nil {
+			return nil, err
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return append(pad(r.Bytes(), size), pad(s.Bytes(), size)...), nil
+	}
+	return nil, errors.New("unsupported signing key")
+}
+
+func verify(alg string, pub crypto.PublicKey, b, sig []byte) error {
+	hash, d, err := digest(alg, b)
+	if err != nil {
+		return err
+	}
+
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return errors.New("algorithm doesn't match key")
+		}
+		return rsa.VerifyPKCS1v15(k, hash, d, sig)
+	case *ecdsa.PublicKey:
+		if alg != ecAlg(k) {
+			return errors.New("algorithm doesn't match key")
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errors.New("invalid signature")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, d, r, s) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+	return errors.New("unsupported key")
+}
+
+func decode(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,158 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/token"
+	"c-z.dev/go-micro/store"
+	"c-z.dev/go-micro/store/memory"
+)
+
+func TestStaticKeys(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, _ := x509.MarshalECPrivateKey(priv)
+	pubDER, _ := x509.MarshalPKIXPublicKey(priv.Public())
+	privKey := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privDER}))
+	pubKey := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+
+	p := NewTokenProvider(token.WithPrivateKey(privKey), token.WithPublicKey(pubKey))
+	tok, err := p.Generate(&auth.Account{ID: "john", Scopes: []string{"admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a provider with only the public key can verify
+	v := NewTokenProvider(token.WithPublicKey(pubKey))
+	acc, err := v.Inspect(tok.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.ID != "john" || len(acc.Scopes) != 1 {
+		t.Fatalf("Unexpected account %+v", acc)
+	}
+	if _, err := v.Generate(acc); err == nil {
+		t.Fatal("Expected error signing without a private key")
+	}
+
+	// tampered tokens are rejected
+	if _, err := v.Inspect(tok.Token[:len(tok.Token)-4] + "AAAA"); err != token.ErrInvalidToken {
+		t.Fatalf("Expected invalid token got %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	st := memory.NewStore()
+	ks := NewKeySet(WithKeyStore(st), WithRotation(time.Hour), WithRetention(time.Hour))
+	p := NewTokenProvider(WithKeySet(ks))
+
+	acc := &auth.Account{ID: "john"}
+	old, err := p.Generate(acc, token.WithExpiry(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ks.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	tok, err := p.Generate(acc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another instance sharing the store verifies tokens signed by both keys
+	other := NewTokenProvider(WithKeySet(NewKeySet(WithKeyStore(st))))
+	for _, tk := range []*token.Token{old, tok} {
+		if _, err := other.Inspect(tk.Token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the keys are served as a JWKS document which remote verifiers use
+	srv := httptest.NewServer(NewHandler(ks))
+	defer srv.Close()
+
+	remote := NewTokenProvider(WithKeySet(RemoteKeys(srv.URL)))
+	for _, tk := range []*token.Token{old, tok} {
+		if _, err := remote.Inspect(tk.Token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys, err := RemoteKeys(srv.URL).Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 keys got %d", len(keys))
+	}
+
+	// keys are retired once the retention period has passed
+	ks = NewKeySet(WithKeyStore(st), WithRotation(time.Hour), WithRetention(time.Nanosecond))
+	if err := ks.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	keys, err = ks.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("Expected retired keys to be removed but got %d keys", len(keys))
+	}
+}
+
+// countingStore counts the reads of the store
+type countingStore struct {
+	store.Store
+	reads int32
+}
+
+func (c *countingStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	atomic.AddInt32(&c.reads, 1)
+	return c.Store.Read(key, opts...)
+}
+
+func TestUnknownKeys(t *testing.T) {
+	st := &countingStore{Store: memory.NewStore()}
+	ks := NewKeySet(WithKeyStore(st))
+	if err := ks.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Keys(); err != nil {
+		t.Fatal(err)
+	}
+	reads := atomic.LoadInt32(&st.reads)
+
+	// made up key ids don't cause a read each
+	for i := 0; i < 10; i++ {
+		if _, err := ks.Verifying(fmt.Sprintf("unknown-%d", i)); err != ErrKeyNotFound {
+			t.Fatalf("Expected key not found got %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&st.reads); n != reads {
+		t.Fatalf("Expected no more reads got %d", n-reads)
+	}
+
+	// a null key set has no keys
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("null"))
+	}))
+	defer srv.Close()
+	if _, err := RemoteKeys(srv.URL).Verifying("foo"); err != ErrKeyNotFound {
+		t.Fatalf("Expected key not found got %v", err)
+	}
+}
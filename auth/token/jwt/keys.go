@@ -0,0 +1,419 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"c-z.dev/go-micro/store"
+)
+
+var (
+	// ErrKeyNotFound is returned when there's no key with the id
+	ErrKeyNotFound = errors.New("key not found")
+
+	// DefaultRotation is how often a new signing key is created
+	DefaultRotation = time.Hour * 24
+	// DefaultRetention is how long a key can still verify tokens once it's
+	// no longer used for signing, it must be longer than the token expiry
+	DefaultRetention = time.Hour * 24
+	// DefaultKeyBits is the size of generated RSA keys
+	DefaultKeyBits = 2048
+	// KeyPrefix of the keys in the store
+	KeyPrefix = "auth/keys/"
+)
+
+// Key is a signing key
+type Key struct {
+	// ID of the key, set as the kid of tokens
+	ID string
+	// Created is when the key was generated
+	Created time.Time
+	// Private key, nil for keys which can only verify
+	Private crypto.Signer
+	// Public key
+	Public crypto.PublicKey
+}
+
+// KeySet holds the keys tokens are signed and verified with
+type KeySet interface {
+	// Signing returns the key new tokens are signed with
+	Signing() (*Key, error)
+	// Verifying returns the key with the id
+	Verifying(id string) (*Key, error)
+	// Keys returns every key tokens can be verified with
+	Keys() ([]*Key, error)
+	// Rotate creates a new signing key, the previous
+	// keys can verify tokens until they're retired
+	Rotate() error
+}
+
+// KeyID returns the RFC 7638 thumbprint of the public key
+func KeyID(pub crypto.PublicKey) string {
+	jwk, err := NewJWK("", pub)
+	if err != nil {
+		return ""
+	}
+	var b []byte
+	switch jwk.Kty {
+	case "RSA":
+		b, _ = json.Marshal(struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N})
+	case "EC":
+		b, _ = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y})
+	}
+	h := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+type staticKeys struct {
+	key *Key
+}
+
+// StaticKeys returns a key set with a single key pair, the keys are base64
+// encoded PEM as set with token.WithPublicKey and token.WithPrivateKey. The
+// private key may be blank to only verify tokens.
+func StaticKeys(public, private string) (KeySet, error) {
+	key := &Key{}
+
+	if len(private) > 0 {
+		priv, err := decodePrivate(private)
+		if err != nil {
+			return nil, err
+		}
+		key.Private = priv
+		key.Public = priv.Public()
+	}
+	if len(public) > 0 {
+		pub, err := decodePublic(public)
+		if err != nil {
+			return nil, err
+		}
+		key.Public = pub
+	}
+	if key.Public == nil {
+		return nil, errors.New("no key is defined")
+	}
+
+	key.ID = KeyID(key.Public)
+	return &staticKeys{key: key}, nil
+}
+
+func (s *staticKeys) Signing() (*Key, error) {
+	if s.key.Private == nil {
+		return nil, errors.New("no private key is defined")
+	}
+	return s.key, nil
+}
+
+func (s *staticKeys) Verifying(id string) (*Key, error) {
+	// tokens signed before key ids were used have none
+	if len(id) == 0 || id == s.key.ID {
+		return s.key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (s *staticKeys) Keys() ([]*Key, error) {
+	return []*Key{s.key}, nil
+}
+
+func (s *staticKeys) Rotate() error {
+	return errors.New("static keys can't be rotated")
+}
+
+// KeySetOptions for the rotating key set
+type KeySetOptions struct {
+	// Store the keys are shared through
+	Store store.Store
+	// Rotation is how often a new signing key is created
+	Rotation time.Duration
+	// Retention is how long retired keys can verify tokens
+	Retention time.Duration
+}
+
+// KeySetOption sets KeySetOptions
+type KeySetOption func(o *KeySetOptions)
+
+// WithKeyStore sets the store the keys are shared through
+func WithKeyStore(s store.Store) KeySetOption {
+	return func(o *KeySetOptions) {
+		o.Store = s
+	}
+}
+
+// WithRotation sets how often a new signing key is created
+func WithRotation(d time.Duration) KeySetOption {
+	return func(o *KeySetOptions) {
+		o.Rotation = d
+	}
+}
+
+// WithRetention sets how long retired keys can verify tokens
+func WithRetention(d time.Duration) KeySetOption {
+	return func(o *KeySetOptions) {
+		o.Retention = d
+	}
+}
+
+type keySet struct {
+	opts KeySetOptions
+
+	sync.Mutex
+	keys   []*Key
+	loaded time.Time
+}
+
+// storedKey is the stored form of a key
+type storedKey struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	Private string    `json:"private"`
+}
+
+// reload is how often the keys are read from the store to pick up
+// keys rotated by other instances
+const reload = time.Minute
+
+// refresh limits how often a token with an unknown key id causes
+// the keys to be read again, so made up ids can't flood the store
+const refresh = time.Second * 10
+
+// NewKeySet returns a key set which creates a new signing key every rotation
+// period. Keys are held in the store so every instance sharing it signs with
+// the same key and can verify tokens signed by the others.
+func NewKeySet(opts ...KeySetOption) KeySet {
+	options := KeySetOptions{
+		Store:     store.DefaultStore,
+		Rotation:  DefaultRotation,
+		Retention: DefaultRetention,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &keySet{opts: options}
+}
+
+func (k *keySet) Signing() (*Key, error) {
+	k.Lock()
+	defer k.Unlock()
+
+	if err := k.load(false); err != nil {
+		return nil, err
+	}
+
+	// rotate when the newest key is due, reloading first
+	// in case another instance already has
+	if len(k.keys) == 0 || time.Since(k.keys[0].Created) > k.opts.Rotation {
+		if err := k.load(true); err != nil {
+			return nil, err
+		}
+		if len(k.keys) == 0 || time.Since(k.keys[0].Created) > k.opts.Rotation {
+			if err := k.rotate(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return k.keys[0], nil
+}
+
+func (k *keySet) Verifying(id string) (*Key, error) {
+	k.Lock()
+	defer k.Unlock()
+
+	if err := k.load(false); err != nil {
+		return nil, err
+	}
+	if key := k.find(id); key != nil {
+		return key, nil
+	}
+
+	// the key may have been created by another instance
+	if time.Since(k.loaded) > refresh {
+		if err := k.load(true); err != nil {
+			return nil, err
+		}
+		if key := k.find(id); key != nil {
+			return key, nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (k *keySet) Keys() ([]*Key, error) {
+	k.Lock()
+	defer k.Unlock()
+
+	if err := k.load(false); err != nil {
+		return nil, err
+	}
+	return append([]*Key{}, k.keys...), nil
+}
+
+func (k *keySet) Rotate() error {
+	k.Lock()
+	defer k.Unlock()
+	return k.rotate()
+}
+
+func (k *keySet) find(id string) *Key {
+	for _, key := range k.keys {
+		if key.ID == id {
+			return key
+		}
+	}
+	return nil
+}
+
+// expired returns whether the key can no longer verify tokens,
+// next is when the key that replaced it was created
+func (k *keySet) expired(next time.Time) bool {
+	return time.Since(next) > k.opts.Retention
+}
+
+// load the keys from the store, newest first, pruning retired keys
+func (k *keySet) load(force bool) error {
+	if !force && time.Since(k.loaded) < reload && len(k.keys) > 0 {
+		return nil
+	}
+
+	recs, err := k.opts.Store.Read(KeyPrefix, store.ReadPrefix())
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+
+	keys := make([]*Key, 0, len(recs))
+	for _, r := range recs {
+		var sk *storedKey
+		if err := json.Unmarshal(r.Value, &sk); err != nil {
+			continue
+		}
+		priv, err := decodePrivate(sk.Private)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, &Key{ID: sk.ID, Created: sk.Created, Private: priv, Public: priv.Public()})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Created.After(keys[j].Created)
+	})
+
+	// a key is retired once the key after it has been signing for the retention period
+	for i := 1; i < len(keys); i++ {
+		if k.expired(keys[i-1].Created) {
+			for _, key := range keys[i:] {
+				k.opts.Store.Delete(KeyPrefix + key.ID)
+			}
+			keys = keys[:i]
+			break
+		}
+	}
+
+	k.keys = keys
+	k.loaded = time.Now()
+	return nil
+}
+
+func (k *keySet) rotate() error {
+	priv, err := rsa.GenerateKey(rand.Reader, DefaultKeyBits)
+	if err != nil {
+		return err
+	}
+	key := &Key{
+		ID:      KeyID(priv.Public()),
+		Created: time.Now(),
+		Private: priv,
+		Public:  priv.Public(),
+	}
+
+	b, err := json.Marshal(&storedKey{
+		ID:      key.ID,
+		Created: key.Created,
+		Private: encodePrivate(priv),
+	})
+	if err != nil {
+		return err
+	}
+	if err := k.opts.Store.Write(&store.Record{Key: KeyPrefix + key.ID, Value: b}); err != nil {
+		return err
+	}
+
+	k.keys = append([]*Key{key}, k.keys...)
+	return nil
+}
+
+func encodePrivate(priv *rsa.PrivateKey) string {
+	b := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func decodePEM(v string) (*pem.Block, error) {
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("invalid PEM key")
+	}
+	return block, nil
+}
+
+func decodePrivate(v string) (crypto.Signer, error) {
+	block, err := decodePEM(v)
+	if err != nil {
+		return nil, err
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := k.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key %T", k)
+	}
+	return s, nil
+}
+
+func decodePublic(v string) (crypto.PublicKey, error) {
+	block, err := decodePEM(v)
+	if err != nil {
+		return nil, err
+	}
+	if k, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
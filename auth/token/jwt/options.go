@@ -0,0 +1,19 @@
+package jwt
+
+import (
+	"context"
+
+	"c-z.dev/go-micro/auth/token"
+)
+
+type keySetKey struct{}
+
+// WithKeySet sets the keys tokens are signed and verified with
+func WithKeySet(ks KeySet) token.Option {
+	return func(o *token.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, keySetKey{}, ks)
+	}
+}
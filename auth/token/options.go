@@ -1,6 +1,7 @@
 package token
 
 import (
+	"context"
 	"time"
 
 	"c-z.dev/go-micro/store"
@@ -13,6 +14,8 @@ type Options struct {
 	PublicKey string
 	// PrivateKey base64 encoded, used by JWT
 	PrivateKey string
+	// Context for other opts
+	Context context.Context
 }
 
 type Option func(o *Options)
@@ -1,6 +1,10 @@
 // Package broker is an interface used for asynchronous messaging
 package broker
 
+import (
+	"errors"
+)
+
 // Broker is an interface used for asynchronous messaging.
 type Broker interface {
 	Init(...Option) error
@@ -38,8 +42,20 @@ type Subscriber interface {
 	Unsubscribe() error
 }
 
+// Scheduler is implemented by brokers which support delayed delivery
+// of messages published with the DeliverAt option.
+type Scheduler interface {
+	// Cancel a scheduled message by its id
+	Cancel(id string) error
+}
+
 var (
 	DefaultBroker Broker = NewBroker()
+
+	// ErrNotScheduled is returned when cancelling an unknown message
+	ErrNotScheduled = errors.New("message not scheduled")
+	// ErrNotSupported is returned when the broker has no scheduler
+	ErrNotSupported = errors.New("scheduling not supported")
 )
 
 func Init(opts ...Option) error {
@@ -62,6 +78,15 @@ func Subscribe(topic string, handler Handler, opts ...SubscribeOption) (Subscrib
 	return DefaultBroker.Subscribe(topic, handler, opts...)
 }
 
+// Cancel a message scheduled on the default broker
+func Cancel(id string) error {
+	s, ok := DefaultBroker.(Scheduler)
+	if !ok {
+		return ErrNotSupported
+	}
+	return s.Cancel(id)
+}
+
 func String() string {
 	return DefaultBroker.String()
 }
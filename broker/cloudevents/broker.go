@@ -0,0 +1,93 @@
+package cloudevents
+
+import (
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/logger"
+)
+
+type cloudeventsBroker struct {
+	broker.Broker
+
+	opts Options
+}
+
+type event struct {
+	broker.Event
+
+	msg *broker.Message
+}
+
+var (
+	// DefaultSource is the source of events when none is set
+	DefaultSource = "go.micro"
+)
+
+func (e *event) Message() *broker.Message {
+	return e.msg
+}
+
+// NewBroker wraps the broker so published messages are sent as
+// CloudEvents and received events, in either mode, are handed to
+// subscribers in binary mode. Subscribers read the event attributes
+// from the message header or, in a server subscriber, via FromContext.
+func NewBroker(b broker.Broker, opts ...Option) broker.Broker {
+	options := Options{
+		Mode:   Binary,
+		Source: DefaultSource,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &cloudeventsBroker{
+		Broker: b,
+		opts:   options,
+	}
+}
+
+func (c *cloudeventsBroker) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	e := newEvent(topic, msg, c.opts.Source)
+
+	m, err := Encode(e, msg.Header, msg.Body, c.opts.Mode)
+	if err != nil {
+		return err
+	}
+
+	return c.Broker.Publish(topic, m, opts...)
+}
+
+func (c *cloudeventsBroker) Subscribe(topic string, h broker.Handler, opts ...broker.SubscribeOption) (broker.Subscriber, error) {
+	fn := func(p broker.Event) error {
+		msg := p.Message()
+		if msg == nil || !IsStructured(msg) {
+			return h(p)
+		}
+
+		e, data, err := Decode(msg)
+		if err != nil {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("[cloudevents] failed to decode event on %s: %v", p.Topic(), err)
+			}
+			return err
+		}
+
+		m, err := Encode(e, msg.Header, data, Binary)
+		if err != nil {
+			return err
+		}
+
+		return h(&event{Event: p, msg: m})
+	}
+
+	return c.Broker.Subscribe(topic, fn, opts...)
+}
+
+// Cancel a scheduled message if the underlying broker supports it
+func (c *cloudeventsBroker) Cancel(id string) error {
+	s, ok := c.Broker.(broker.Scheduler)
+	if !ok {
+		return broker.ErrNotSupported
+	}
+	return s.Cancel(id)
+}
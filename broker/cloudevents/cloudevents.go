@@ -0,0 +1,361 @@
+// Package cloudevents encodes broker messages as CloudEvents 1.0
+package cloudevents
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/metadata"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Mode is the content mode of an event
+type Mode int
+
+const (
+	// Binary mode carries attributes in the message header
+	// and the event data as the message body
+	Binary Mode = iota
+	// Structured mode carries the whole event as a JSON document
+	Structured
+)
+
+const (
+	// SpecVersion is the supported CloudEvents version
+	SpecVersion = "1.0"
+	// ContentType of events in structured mode
+	ContentType = "application/cloudevents+json"
+
+	// HeaderPrefix of attributes in binary mode
+	HeaderPrefix = "Ce-"
+)
+
+var (
+	// ErrInvalidEvent is returned when a message is not a valid event
+	ErrInvalidEvent = errors.New("invalid cloudevent")
+)
+
+// Event holds the context attributes of a CloudEvent
+type Event struct {
+	Id              string
+	Source          string
+	SpecVersion     string
+	Type            string
+	DataContentType string
+	DataSchema      string
+	Subject         string
+	Time            time.Time
+	// Extensions are any additional attributes
+	Extensions map[string]string
+}
+
+// structured is the JSON representation of an event
+type structured struct {
+	Id              string          `json:"id"`
+	Source          string          `json:"source"`
+	SpecVersion     string          `json:"specversion"`
+	Type            string          `json:"type"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataSchema      string          `json:"dataschema,omitempty"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	DataBase64      string          `json:"data_base64,omitempty"`
+}
+
+// attributes maps header names to the required and optional attributes
+var attributes = map[string]func(e *Event) *string{
+	"id":          func(e *Event) *string { return &e.Id },
+	"source":      func(e *Event) *string { return &e.Source },
+	"specversion": func(e *Event) *string { return &e.SpecVersion },
+	"type":        func(e *Event) *string { return &e.Type },
+	"dataschema":  func(e *Event) *string { return &e.DataSchema },
+	"subject":     func(e *Event) *string { return &e.Subject },
+}
+
+func isJSON(ct string) bool {
+	ct = strings.ToLower(ct)
+	return len(ct) == 0 || strings.HasPrefix(ct, "application/json") || strings.HasSuffix(strings.Split(ct, ";")[0], "+json")
+}
+
+// IsStructured reports whether the message is an event in structured mode
+func IsStructured(msg *broker.Message) bool {
+	return strings.HasPrefix(contentType(msg.Header), ContentType)
+}
+
+// IsBinary reports whether the message is an event in binary mode
+func IsBinary(msg *broker.Message) bool {
+	for k := range msg.Header {
+		if strings.EqualFold(k, HeaderPrefix+"specversion") {
+			return true
+		}
+	}
+	return false
+}
+
+func contentType(hdr map[string]string) string {
+	for k, v := range hdr {
+		if strings.EqualFold(k, "Content-Type") {
+			return v
+		}
+	}
+	return ""
+}
+
+// FromHeader extracts the event attributes from binary mode headers
+func FromHeader(hdr map[string]string) (*Event, bool) {
+	e := &Event{
+		DataContentType: contentType(hdr),
+		Extensions:      make(map[string]string),
+	}
+
+	for k, v := range hdr {
+		if len(k) <= len(HeaderPrefix) || !strings.EqualFold(k[:len(HeaderPrefix)], HeaderPrefix) {
+			continue
+		}
+		name := strings.ToLower(k[len(HeaderPrefix):])
+		if fn, ok := attributes[name]; ok {
+			*fn(e) = v
+			continue
+		}
+		if name == "time" {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, false
+			}
+			e.Time = t
+			continue
+		}
+		e.Extensions[name] = v
+	}
+
+	if len(e.SpecVersion) == 0 {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// Header returns the binary mode headers for the event attributes
+func (e *Event) Header() map[string]string {
+	hdr := make(map[string]string)
+	set := func(k, v string) {
+		if len(v) > 0 {
+			hdr[HeaderPrefix+cases.Title(language.English).String(k)] = v
+		}
+	}
+	for name, fn := range attributes {
+		set(name, *fn(e))
+	}
+	if !e.Time.IsZero() {
+		set("time", e.Time.Format(time.RFC3339Nano))
+	}
+	for k, v := range e.Extensions {
+		set(strings.ToLower(k), v)
+	}
+	if len(e.DataContentType) > 0 {
+		hdr["Content-Type"] = e.DataContentType
+	}
+	return hdr
+}
+
+// Encode the event and data as a message in the given mode.
+// Headers which are not event attributes are copied from hdr.
+func Encode(e *Event, hdr map[string]string, data []byte, mode Mode) (*broker.Message, error) {
+	if len(e.SpecVersion) == 0 {
+		e.SpecVersion = SpecVersion
+	}
+	if len(e.Id) == 0 || len(e.Source) == 0 || len(e.Type) == 0 {
+		return nil, ErrInvalidEvent
+	}
+
+	msg := &broker.Message{
+		Header: make(map[string]string),
+	}
+	for k, v := range hdr {
+		if strings.EqualFold(k, "Content-Type") {
+			continue
+		}
+		if len(k) > len(HeaderPrefix) && strings.EqualFold(k[:len(HeaderPrefix)], HeaderPrefix) {
+			continue
+		}
+		msg.Header[k] = v
+	}
+
+	if mode == Binary {
+		for k, v := range e.Header() {
+			msg.Header[k] = v
+		}
+		msg.Body = data
+		return msg, nil
+	}
+
+	s := &structured{
+		Id:              e.Id,
+		Source:          e.Source,
+		SpecVersion:     e.SpecVersion,
+		Type:            e.Type,
+		DataContentType: e.DataContentType,
+		DataSchema:      e.DataSchema,
+		Subject:         e.Subject,
+	}
+	if !e.Time.IsZero() {
+		s.Time = e.Time.Format(time.RFC3339Nano)
+	}
+	if len(data) > 0 {
+		if isJSON(e.DataContentType) && json.Valid(data) {
+			s.Data = data
+		} else {
+			s.DataBase64 = base64.StdEncoding.EncodeToString(data)
+		}
+	}
+
+	// extensions are top level attributes
+	v := make(map[string]interface{})
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	for k, ext := range e.Extensions {
+		v[strings.ToLower(k)] = ext
+	}
+
+	b, err = json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	msg.Header["Content-Type"] = ContentType
+	msg.Body = b
+	return msg, nil
+}
+
+// Decode a message in either mode into the event attributes and its data
+func Decode(msg *broker.Message) (*Event, []byte, error) {
+	if !IsStructured(msg) {
+		e, ok := FromHeader(msg.Header)
+		if !ok {
+			return nil, nil, ErrInvalidEvent
+		}
+		return e, msg.Body, nil
+	}
+
+	var s structured
+	if err := json.Unmarshal(msg.Body, &s); err != nil {
+		return nil, nil, err
+	}
+	if len(s.SpecVersion) == 0 {
+		return nil, nil, ErrInvalidEvent
+	}
+
+	var ext map[string]interface{}
+	if err := json.Unmarshal(msg.Body, &ext); err != nil {
+		return nil, nil, err
+	}
+
+	e := &Event{
+		Id:              s.Id,
+		Source:          s.Source,
+		SpecVersion:     s.SpecVersion,
+		Type:            s.Type,
+		DataContentType: s.DataContentType,
+		DataSchema:      s.DataSchema,
+		Subject:         s.Subject,
+		Extensions:      make(map[string]string),
+	}
+	if len(s.Time) > 0 {
+		t, err := time.Parse(time.RFC3339Nano, s.Time)
+		if err != nil {
+			return nil, nil, err
+		}
+		e.Time = t
+	}
+
+	for k, v := range ext {
+		switch k {
+		case "id", "source", "specversion", "type", "datacontenttype",
+			"dataschema", "subject", "time", "data", "data_base64":
+			continue
+		}
+		if str, ok := v.(string); ok {
+			e.Extensions[k] = str
+		}
+	}
+
+	var data []byte
+	switch {
+	case len(s.DataBase64) > 0:
+		b, err := base64.StdEncoding.DecodeString(s.DataBase64)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = b
+	case len(s.Data) > 0:
+		// json strings are unquoted unless the content is json
+		var str string
+		if !isJSON(e.DataContentType) && json.Unmarshal(s.Data, &str) == nil {
+			data = []byte(str)
+		} else {
+			data = s.Data
+		}
+		if len(e.DataContentType) == 0 {
+			e.DataContentType = "application/json"
+		}
+	}
+
+	return e, data, nil
+}
+
+// FromContext returns the attributes of the event being handled.
+// They are read from the metadata set by the server for a subscriber.
+func FromContext(ctx context.Context) (*Event, bool) {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	return FromHeader(md)
+}
+
+// newEvent returns an event with the attributes of a micro message
+func newEvent(topic string, msg *broker.Message, source string) *Event {
+	e, ok := FromHeader(msg.Header)
+	if !ok {
+		e = &Event{
+			SpecVersion:     SpecVersion,
+			DataContentType: contentType(msg.Header),
+			Extensions:      make(map[string]string),
+		}
+	}
+
+	if len(e.Id) == 0 {
+		e.Id = msg.Header["Micro-Id"]
+	}
+	if len(e.Id) == 0 {
+		e.Id = uuid.New().String()
+	}
+	if len(e.Source) == 0 {
+		e.Source = source
+	}
+	if len(e.Type) == 0 {
+		e.Type = msg.Header["Micro-Topic"]
+	}
+	if len(e.Type) == 0 {
+		e.Type = topic
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	return e
+}
@@ -0,0 +1,140 @@
+package cloudevents
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/broker/memory"
+	"c-z.dev/go-micro/metadata"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	testData := []struct {
+		name        string
+		contentType string
+		data        []byte
+	}{
+		{"json", "application/json", []byte(`{"name":"john"}`)},
+		{"text", "text/plain", []byte(`hello`)},
+		{"protobuf", "application/protobuf", []byte{0x0a, 0x04, 0x6a, 0x6f, 0x68, 0x6e}},
+	}
+
+	for _, mode := range []Mode{Binary, Structured} {
+		for _, d := range testData {
+			e := &Event{
+				Id:              "1",
+				Source:          "/orders",
+				Type:            "orders.created",
+				Subject:         "order-1",
+				DataContentType: d.contentType,
+				Time:            now,
+				Extensions:      map[string]string{"tenant": "acme"},
+			}
+
+			msg, err := Encode(e, map[string]string{"Micro-Id": "1"}, d.data, mode)
+			if err != nil {
+				t.Fatalf("%s: unexpected encode error %v", d.name, err)
+			}
+			if msg.Header["Micro-Id"] != "1" {
+				t.Fatalf("%s: expected headers to be kept got %v", d.name, msg.Header)
+			}
+			if mode == Structured && !IsStructured(msg) {
+				t.Fatalf("%s: expected structured message got %v", d.name, msg.Header)
+			}
+			if mode == Binary && !IsBinary(msg) {
+				t.Fatalf("%s: expected binary message got %v", d.name, msg.Header)
+			}
+
+			de, data, err := Decode(msg)
+			if err != nil {
+				t.Fatalf("%s: unexpected decode error %v", d.name, err)
+			}
+			if string(data) != string(d.data) {
+				t.Fatalf("%s: expected data %q got %q", d.name, d.data, data)
+			}
+			if de.Id != e.Id || de.Source != e.Source || de.Type != e.Type || de.Subject != e.Subject {
+				t.Fatalf("%s: expected %+v got %+v", d.name, e, de)
+			}
+			if de.SpecVersion != SpecVersion || de.DataContentType != d.contentType || !de.Time.Equal(now) {
+				t.Fatalf("%s: expected %+v got %+v", d.name, e, de)
+			}
+			if de.Extensions["tenant"] != "acme" {
+				t.Fatalf("%s: expected extension tenant got %v", d.name, de.Extensions)
+			}
+		}
+	}
+
+	if _, err := Encode(&Event{Id: "1"}, nil, nil, Binary); err != ErrInvalidEvent {
+		t.Fatalf("Expected %v got %v", ErrInvalidEvent, err)
+	}
+	if _, _, err := Decode(&broker.Message{Body: []byte("hello")}); err != ErrInvalidEvent {
+		t.Fatalf("Expected %v got %v", ErrInvalidEvent, err)
+	}
+}
+
+func TestBroker(t *testing.T) {
+	m := memory.NewBroker()
+	b := NewBroker(m, WithMode(Structured), WithSource("/test"))
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+	defer b.Disconnect()
+
+	raw := make(chan *broker.Message, 1)
+	received := make(chan *broker.Message, 1)
+
+	// a non micro consumer sees the structured event
+	if _, err := m.Subscribe("orders.created", func(p broker.Event) error {
+		raw <- p.Message()
+		return nil
+	}); err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+
+	if _, err := b.Subscribe("orders.created", func(p broker.Event) error {
+		received <- p.Message()
+		return nil
+	}); err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+
+	err := b.Publish("orders.created", &broker.Message{
+		Header: map[string]string{
+			"Content-Type": "application/json",
+			"Micro-Id":     "123",
+			"Micro-Topic":  "orders.created",
+		},
+		Body: []byte(`{"id":"order-1"}`),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected publish error %v", err)
+	}
+
+	msg := <-raw
+	if !IsStructured(msg) {
+		t.Fatalf("Expected structured event got %v", msg.Header)
+	}
+
+	msg = <-received
+	if msg.Header["Content-Type"] != "application/json" || string(msg.Body) != `{"id":"order-1"}` {
+		t.Fatalf("Unexpected message %v %s", msg.Header, msg.Body)
+	}
+
+	// the server puts the message header in the context metadata
+	e, ok := FromContext(metadata.NewContext(context.Background(), msg.Header))
+	if !ok {
+		t.Fatalf("Expected event in context got %v", msg.Header)
+	}
+	if e.Id != "123" || e.Source != "/test" || e.Type != "orders.created" || e.Time.IsZero() {
+		t.Fatalf("Unexpected event %+v", e)
+	}
+
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatal("Expected no event in empty context")
+	}
+}
@@ -0,0 +1,24 @@
+package cloudevents
+
+type Options struct {
+	// Mode used to encode published messages
+	Mode Mode
+	// Source is the default source attribute of published events
+	Source string
+}
+
+type Option func(o *Options)
+
+// WithMode sets the content mode used for published messages
+func WithMode(m Mode) Option {
+	return func(o *Options) {
+		o.Mode = m
+	}
+}
+
+// WithSource sets the default source attribute of published events
+func WithSource(s string) Option {
+	return func(o *Options) {
+		o.Source = s
+	}
+}
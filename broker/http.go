@@ -334,11 +334,16 @@ func (h *httpBroker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	var subs []Handler
 
 	h.RLock()
-	for _, subscriber := range h.subscribers[topic] {
-		if id != subscriber.id {
+	for pattern, subscribers := range h.subscribers {
+		if !Match(pattern, topic) {
 			continue
 		}
-		subs = append(subs, subscriber.fn)
+		for _, subscriber := range subscribers {
+			if id != subscriber.id {
+				continue
+			}
+			subs = append(subs, subscriber.fn)
+		}
 	}
 	h.RUnlock()
 
@@ -574,8 +579,8 @@ func (h *httpBroker) Publish(topic string, msg *Message, opts ...PublishOption)
 					continue
 				}
 
-				// look for nodes for the topic
-				if node.Metadata["topic"] != topic {
+				// look for nodes subscribed to the topic
+				if !Match(node.Metadata["topic"], topic) {
 					continue
 				}
 
@@ -23,6 +23,9 @@ type memoryBroker struct {
 	sync.RWMutex
 	connected   bool
 	Subscribers map[string][]*memorySubscriber
+
+	// messages scheduled for delayed delivery
+	scheduled map[string]*time.Timer
 }
 
 type memoryEvent struct {
@@ -81,6 +84,12 @@ func (m *memoryBroker) Disconnect() error {
 
 	m.connected = false
 
+	// drop any messages waiting for delivery
+	for id, t := range m.scheduled {
+		t.Stop()
+		delete(m.scheduled, id)
+	}
+
 	return nil
 }
 
@@ -92,15 +101,64 @@ func (m *memoryBroker) Init(opts ...broker.Option) error {
 }
 
 func (m *memoryBroker) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	options := broker.NewPublishOptions(opts...)
+
+	m.Lock()
+	if !m.connected {
+		m.Unlock()
+		return errors.New("not connected")
+	}
+
+	// schedule the message for later delivery
+	if d := time.Until(options.DeliverAt); d > 0 {
+		id := options.MessageId
+		if len(id) == 0 {
+			id = uuid.New().String()
+		}
+		if t, ok := m.scheduled[id]; ok {
+			t.Stop()
+		}
+		var t *time.Timer
+		t = time.AfterFunc(d, func() {
+			m.Lock()
+			// cancelled or replaced while firing
+			if m.scheduled[id] != t {
+				m.Unlock()
+				return
+			}
+			delete(m.scheduled, id)
+			m.Unlock()
+
+			if err := m.publish(topic, msg); err != nil {
+				if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+					logger.Errorf("[memory]: failed to deliver scheduled message %s: %v", id, err)
+				}
+			}
+		})
+		m.scheduled[id] = t
+		m.Unlock()
+		return nil
+	}
+	m.Unlock()
+
+	return m.publish(topic, msg)
+}
+
+func (m *memoryBroker) publish(topic string, msg *broker.Message) error {
 	m.RLock()
 	if !m.connected {
 		m.RUnlock()
 		return errors.New("not connected")
 	}
 
-	subs, ok := m.Subscribers[topic]
+	var subs []*memorySubscriber
+	for pattern, sub := range m.Subscribers {
+		if broker.Match(pattern, topic) {
+			subs = append(subs, sub...)
+		}
+	}
 	m.RUnlock()
-	if !ok {
+	if len(subs) == 0 {
 		return nil
 	}
 
@@ -177,6 +235,21 @@ func (m *memoryBroker) Subscribe(topic string, handler broker.Handler, opts ...b
 	return sub, nil
 }
 
+// Cancel a message scheduled for delayed delivery
+func (m *memoryBroker) Cancel(id string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	t, ok := m.scheduled[id]
+	if !ok {
+		return broker.ErrNotScheduled
+	}
+	t.Stop()
+	delete(m.scheduled, id)
+
+	return nil
+}
+
 func (m *memoryBroker) String() string {
 	return "memory"
 }
@@ -237,5 +310,6 @@ func NewBroker(opts ...broker.Option) broker.Broker {
 	return &memoryBroker{
 		opts:        options,
 		Subscribers: make(map[string][]*memorySubscriber),
+		scheduled:   make(map[string]*time.Timer),
 	}
 }
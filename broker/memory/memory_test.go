@@ -2,7 +2,9 @@ package memory
 
 import (
 	"fmt"
+	"sort"
 	"testing"
+	"time"
 
 	"c-z.dev/go-micro/broker"
 )
@@ -48,3 +50,95 @@ func TestMemoryBroker(t *testing.T) {
 		t.Fatalf("Unexpected connect error %v", err)
 	}
 }
+
+func TestMemoryBrokerDeliverAt(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+	defer b.Disconnect()
+
+	received := make(chan string, 2)
+
+	sub, err := b.Subscribe("delayed", func(p broker.Event) error {
+		received <- p.Message().Header["id"]
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	publish := func(id string) {
+		msg := &broker.Message{Header: map[string]string{"id": id}}
+		if err := b.Publish("delayed", msg, broker.DeliverAfter(time.Millisecond*50), broker.MessageId(id)); err != nil {
+			t.Fatalf("Unexpected error publishing %s: %v", id, err)
+		}
+	}
+
+	publish("1")
+	publish("2")
+
+	if err := b.(broker.Scheduler).Cancel("2"); err != nil {
+		t.Fatalf("Unexpected error cancelling: %v", err)
+	}
+	if err := b.(broker.Scheduler).Cancel("2"); err != broker.ErrNotScheduled {
+		t.Fatalf("Expected %v got %v", broker.ErrNotScheduled, err)
+	}
+
+	select {
+	case id := <-received:
+		t.Fatalf("Message %s delivered before its delivery time", id)
+	default:
+	}
+
+	select {
+	case id := <-received:
+		if id != "1" {
+			t.Fatalf("Expected message 1 got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for scheduled message")
+	}
+
+	select {
+	case id := <-received:
+		t.Fatalf("Cancelled message %s was delivered", id)
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func TestMemoryBrokerPattern(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+	defer b.Disconnect()
+
+	var topics []string
+
+	for _, pattern := range []string{"orders.*", "orders.>"} {
+		sub, err := b.Subscribe(pattern, func(p broker.Event) error {
+			topics = append(topics, p.Topic())
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error subscribing %v", err)
+		}
+		defer sub.Unsubscribe()
+	}
+
+	for _, topic := range []string{"orders.created", "orders.eu.created", "users.created"} {
+		if err := b.Publish(topic, &broker.Message{Body: []byte(topic)}); err != nil {
+			t.Fatalf("Unexpected error publishing %s: %v", topic, err)
+		}
+	}
+
+	sort.Strings(topics)
+	expected := []string{"orders.created", "orders.created", "orders.eu.created"}
+	if fmt.Sprint(topics) != fmt.Sprint(expected) {
+		t.Fatalf("Expected %v got %v", expected, topics)
+	}
+}
@@ -3,6 +3,7 @@ package broker
 import (
 	"context"
 	"crypto/tls"
+	"time"
 
 	"c-z.dev/go-micro/codec"
 	"c-z.dev/go-micro/registry"
@@ -26,6 +27,13 @@ type Options struct {
 }
 
 type PublishOptions struct {
+	// DeliverAt is the earliest time at which the message
+	// should be delivered. A zero value delivers immediately.
+	DeliverAt time.Time
+	// MessageId identifies a scheduled message so that
+	// it can be cancelled before delivery
+	MessageId string
+
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
@@ -49,6 +57,39 @@ type Option func(*Options)
 
 type PublishOption func(*PublishOptions)
 
+func NewPublishOptions(opts ...PublishOption) PublishOptions {
+	opt := PublishOptions{
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	return opt
+}
+
+// DeliverAt schedules the message for delivery at the given time
+func DeliverAt(t time.Time) PublishOption {
+	return func(o *PublishOptions) {
+		o.DeliverAt = t
+	}
+}
+
+// DeliverAfter schedules the message for delivery after the given delay
+func DeliverAfter(d time.Duration) PublishOption {
+	return func(o *PublishOptions) {
+		o.DeliverAt = time.Now().Add(d)
+	}
+}
+
+// MessageId sets the id used to cancel a scheduled message
+func MessageId(id string) PublishOption {
+	return func(o *PublishOptions) {
+		o.MessageId = id
+	}
+}
+
 // PublishContext set context
 func PublishContext(ctx context.Context) PublishOption {
 	return func(o *PublishOptions) {
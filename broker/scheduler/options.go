@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"time"
+
+	"c-z.dev/go-micro/store"
+)
+
+type Options struct {
+	// Store used to stage scheduled messages
+	Store store.Store
+	// Database and Table the messages are written to
+	Database, Table string
+	// Interval at which the store is checked for due messages
+	Interval time.Duration
+}
+
+type Option func(o *Options)
+
+// Store sets the store used to stage scheduled messages
+func Store(s store.Store) Option {
+	return func(o *Options) {
+		o.Store = s
+	}
+}
+
+// Table sets the database and table scheduled messages are written to
+func Table(database, table string) Option {
+	return func(o *Options) {
+		o.Database = database
+		o.Table = table
+	}
+}
+
+// Interval sets how often the store is checked for due messages
+func Interval(d time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = d
+	}
+}
@@ -0,0 +1,196 @@
+// Package scheduler provides delayed delivery for any broker by staging messages in a store
+package scheduler
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/logger"
+	"c-z.dev/go-micro/store"
+	"c-z.dev/go-micro/store/memory"
+
+	"github.com/google/uuid"
+)
+
+type scheduler struct {
+	broker.Broker
+
+	opts Options
+
+	sync.Mutex
+	exit chan bool
+}
+
+type message struct {
+	Id        string          `json:"id"`
+	Topic     string          `json:"topic"`
+	Message   *broker.Message `json:"message"`
+	DeliverAt time.Time       `json:"deliver_at"`
+}
+
+var (
+	// DefaultInterval is the default interval at which due messages are released
+	DefaultInterval = time.Second
+)
+
+// NewBroker wraps the broker so messages published with broker.DeliverAt
+// are staged in the store and released once they are due. Scheduled
+// messages are delivered at least once; when multiple schedulers share
+// a store a message may be released by more than one of them.
+func NewBroker(b broker.Broker, opts ...Option) broker.Broker {
+	options := Options{
+		Interval: DefaultInterval,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.Store == nil {
+		options.Store = memory.NewStore()
+	}
+
+	return &scheduler{
+		Broker: b,
+		opts:   options,
+	}
+}
+
+func (s *scheduler) Connect() error {
+	if err := s.Broker.Connect(); err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.exit != nil {
+		return nil
+	}
+
+	s.exit = make(chan bool)
+	go s.run(s.exit)
+
+	return nil
+}
+
+func (s *scheduler) Disconnect() error {
+	s.Lock()
+	if s.exit != nil {
+		close(s.exit)
+		s.exit = nil
+	}
+	s.Unlock()
+
+	return s.Broker.Disconnect()
+}
+
+func (s *scheduler) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	options := broker.NewPublishOptions(opts...)
+
+	// deliver immediately
+	if !options.DeliverAt.After(time.Now()) {
+		return s.Broker.Publish(topic, msg, opts...)
+	}
+
+	id := options.MessageId
+	if len(id) == 0 {
+		id = msg.Header["Micro-Id"]
+	}
+	if len(id) == 0 {
+		id = uuid.New().String()
+	}
+
+	b, err := json.Marshal(&message{
+		Id:        id,
+		Topic:     topic,
+		Message:   msg,
+		DeliverAt: options.DeliverAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.opts.Store.Write(&store.Record{
+		Key:   id,
+		Value: b,
+	}, store.WriteTo(s.opts.Database, s.opts.Table))
+}
+
+// Cancel a scheduled message by its id
+func (s *scheduler) Cancel(id string) error {
+	recs, err := s.opts.Store.Read(id, store.ReadFrom(s.opts.Database, s.opts.Table))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return broker.ErrNotScheduled
+	} else if err != nil {
+		return err
+	}
+
+	return s.opts.Store.Delete(id, store.DeleteFrom(s.opts.Database, s.opts.Table))
+}
+
+// release publishes every message which is due
+func (s *scheduler) release() error {
+	keys, err := s.opts.Store.List(store.ListFrom(s.opts.Database, s.opts.Table))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, key := range keys {
+		recs, err := s.opts.Store.Read(key, store.ReadFrom(s.opts.Database, s.opts.Table))
+		if err != nil || len(recs) == 0 {
+			// cancelled or released elsewhere
+			continue
+		}
+
+		var m *message
+		if err := json.Unmarshal(recs[0].Value, &m); err != nil {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("[scheduler] failed to decode message %s: %v", key, err)
+			}
+			continue
+		}
+
+		if m.DeliverAt.After(now) {
+			continue
+		}
+
+		if err := s.opts.Store.Delete(key, store.DeleteFrom(s.opts.Database, s.opts.Table)); err != nil {
+			return err
+		}
+
+		if err := s.Broker.Publish(m.Topic, m.Message); err != nil {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("[scheduler] failed to publish message %s: %v", key, err)
+			}
+			// put it back to retry on the next tick
+			if err := s.opts.Store.Write(recs[0], store.WriteTo(s.opts.Database, s.opts.Table)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *scheduler) run(exit chan bool) {
+	t := time.NewTicker(s.opts.Interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case <-t.C:
+			if err := s.release(); err != nil {
+				if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+					logger.Errorf("[scheduler] failed to release messages: %v", err)
+				}
+			}
+		}
+	}
+}
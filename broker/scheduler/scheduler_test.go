@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/broker/memory"
+	smemory "c-z.dev/go-micro/store/memory"
+)
+
+func TestScheduler(t *testing.T) {
+	st := smemory.NewStore()
+	b := NewBroker(memory.NewBroker(), Store(st), Interval(time.Millisecond*10))
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+	defer b.Disconnect()
+
+	received := make(chan string, 3)
+
+	sub, err := b.Subscribe("reminders", func(p broker.Event) error {
+		received <- string(p.Message().Body)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	publish := func(body string, opts ...broker.PublishOption) {
+		if err := b.Publish("reminders", &broker.Message{Body: []byte(body)}, opts...); err != nil {
+			t.Fatalf("Unexpected error publishing %s: %v", body, err)
+		}
+	}
+
+	publish("later", broker.DeliverAfter(time.Millisecond*100), broker.MessageId("later"))
+	publish("cancelled", broker.DeliverAfter(time.Millisecond*100), broker.MessageId("cancelled"))
+	publish("now")
+
+	if keys, err := st.List(); err != nil || len(keys) != 2 {
+		t.Fatalf("Expected 2 staged messages got %v: %v", keys, err)
+	}
+
+	if err := b.(broker.Scheduler).Cancel("cancelled"); err != nil {
+		t.Fatalf("Unexpected error cancelling: %v", err)
+	}
+	if err := b.(broker.Scheduler).Cancel("unknown"); err != broker.ErrNotScheduled {
+		t.Fatalf("Expected %v got %v", broker.ErrNotScheduled, err)
+	}
+
+	if body := <-received; body != "now" {
+		t.Fatalf("Expected immediate message got %s", body)
+	}
+
+	select {
+	case body := <-received:
+		if body != "later" {
+			t.Fatalf("Expected scheduled message got %s", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for scheduled message")
+	}
+
+	select {
+	case body := <-received:
+		t.Fatalf("Unexpected message %s", body)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	if keys, _ := st.List(); len(keys) != 0 {
+		t.Fatalf("Expected no staged messages got %v", keys)
+	}
+}
@@ -78,8 +78,14 @@ func (s *serviceSub) run() error {
 			return err
 		}
 
+		// report the concrete topic for pattern subscriptions
+		topic := s.topic
+		if t := msg.Header["Micro-Topic"]; len(t) > 0 && broker.Match(s.topic, t) {
+			topic = t
+		}
+
 		p := &serviceEvent{
-			topic: s.topic,
+			topic: topic,
 			message: &broker.Message{
 				Header: msg.Header,
 				Body:   msg.Body,
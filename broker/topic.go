@@ -0,0 +1,45 @@
+package broker
+
+import (
+	"strings"
+)
+
+// Match reports whether a topic matches a subscription pattern. Topics are
+// made of tokens separated by dots. In a pattern * matches exactly one token
+// and > matches one or more trailing tokens, e.g. orders.* matches
+// orders.created and orders.> matches orders.eu.created.
+func Match(pattern, topic string) bool {
+	if pattern == topic {
+		return true
+	}
+
+	pt := strings.Split(pattern, ".")
+	tt := strings.Split(topic, ".")
+
+	for i, p := range pt {
+		switch {
+		case p == ">" && i == len(pt)-1:
+			return len(tt) > i
+		case i >= len(tt):
+			return false
+		case p == "*":
+			if len(tt[i]) == 0 {
+				return false
+			}
+		case p != tt[i]:
+			return false
+		}
+	}
+
+	return len(pt) == len(tt)
+}
+
+// IsPattern reports whether the topic contains wildcards
+func IsPattern(topic string) bool {
+	for _, t := range strings.Split(topic, ".") {
+		if t == "*" || t == ">" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package broker
+
+import (
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	testData := []struct {
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{"orders", "orders", true},
+		{"orders", "orders.created", false},
+		{"orders.*", "orders.created", true},
+		{"orders.*", "orders", false},
+		{"orders.*", "orders.eu.created", false},
+		{"orders.*.created", "orders.eu.created", true},
+		{"orders.*.created", "orders.eu.deleted", false},
+		{"orders.>", "orders.created", true},
+		{"orders.>", "orders.eu.created", true},
+		{"orders.>", "orders", false},
+		{"*.created", "orders.created", true},
+		{">", "orders.created", true},
+		{"orders.>.created", "orders.eu.created", false},
+		{"go.micro.srv", "go.micro.srv", true},
+		{"go.micro.*", "go.micro.srv", true},
+		{"go.micro.*", "go.micro.", false},
+	}
+
+	for _, d := range testData {
+		if m := Match(d.pattern, d.topic); m != d.match {
+			t.Fatalf("Expected %s match %s to be %t got %t", d.pattern, d.topic, d.match, m)
+		}
+	}
+}
+
+func TestIsPattern(t *testing.T) {
+	if IsPattern("orders.created") {
+		t.Fatal("Expected orders.created not to be a pattern")
+	}
+	if !IsPattern("orders.*") || !IsPattern("orders.>") {
+		t.Fatal("Expected wildcard topics to be patterns")
+	}
+}
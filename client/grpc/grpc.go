@@ -631,6 +631,9 @@ func (g *grpcClient) Publish(ctx context.Context, p client.Message, opts ...clie
 	}
 	md["Content-Type"] = p.ContentType()
 	md["Micro-Topic"] = p.Topic()
+	if len(options.MessageId) > 0 {
+		md["Micro-Id"] = options.MessageId
+	}
 
 	cf, err := g.newGRPCCodec(p.ContentType())
 	if err != nil {
@@ -669,7 +672,10 @@ func (g *grpcClient) Publish(ctx context.Context, p client.Message, opts ...clie
 		topic, &broker.Message{
 			Header: md,
 			Body:   body,
-		}, broker.PublishContext(options.Context),
+		},
+		broker.PublishContext(options.Context),
+		broker.DeliverAt(options.DeliverAt),
+		broker.MessageId(options.MessageId),
 	)
 }
 
@@ -64,6 +64,8 @@ type CallOptions struct {
 	ServiceToken bool
 	// Duration to cache the response for
 	CacheExpiry time.Duration
+	// Make the call over the broker rather than the transport
+	BrokerCall bool
 
 	// Middleware for low level call func
 	CallWrappers []CallWrapper
@@ -76,6 +78,10 @@ type CallOptions struct {
 type PublishOptions struct {
 	// Exchange is the routing exchange for the message
 	Exchange string
+	// DeliverAt delays delivery of the message until the given time
+	DeliverAt time.Time
+	// MessageId is the id of the message, used to cancel a delayed delivery
+	MessageId string
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
@@ -248,6 +254,20 @@ func WithExchange(e string) PublishOption {
 	}
 }
 
+// WithDeliverAt delays delivery of the message until the given time
+func WithDeliverAt(t time.Time) PublishOption {
+	return func(o *PublishOptions) {
+		o.DeliverAt = t
+	}
+}
+
+// WithMessageId sets the id of the published message
+func WithMessageId(id string) PublishOption {
+	return func(o *PublishOptions) {
+		o.MessageId = id
+	}
+}
+
 // PublishContext sets the context in publish options
 func PublishContext(ctx context.Context) PublishOption {
 	return func(o *PublishOptions) {
@@ -338,6 +358,16 @@ func WithCache(c time.Duration) CallOption {
 	}
 }
 
+// WithBrokerCall is a CallOption which makes the call over the
+// broker using a reply topic, for servers started with
+// server.BrokerCalls. It can be used when a caller can't hold a
+// transport connection open for long running work.
+func WithBrokerCall() CallOption {
+	return func(o *CallOptions) {
+		o.BrokerCall = true
+	}
+}
+
 func WithMessageContentType(ct string) MessageOption {
 	return func(o *MessageOptions) {
 		o.ContentType = ct
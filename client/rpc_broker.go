@@ -0,0 +1,252 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/errors"
+	"c-z.dev/go-micro/metadata"
+	"c-z.dev/go-micro/transport"
+
+	"github.com/google/uuid"
+)
+
+// brokerReplies receives the responses to calls made over the broker
+// on a single reply topic and hands them to the waiting call
+type brokerReplies struct {
+	sync.Mutex
+	topic   string
+	sub     broker.Subscriber
+	pending map[string]chan *broker.Message
+}
+
+// brokerClient is a transport client which sends a request
+// over the broker and receives the response on the reply topic
+type brokerClient struct {
+	broker  broker.Broker
+	replies *brokerReplies
+	topic   string
+	id      string
+	rsp     chan *broker.Message
+	once    sync.Once
+	exit    chan bool
+}
+
+// callTopic is the broker topic a service receives calls on
+func callTopic(service string) string {
+	return "go.micro.call." + service
+}
+
+func (b *brokerReplies) handle(e broker.Event) error {
+	msg := e.Message()
+	id := msg.Header["Micro-Correlation-Id"]
+
+	b.Lock()
+	ch, ok := b.pending[id]
+	b.Unlock()
+
+	if !ok {
+		// the call timed out or was not made by us
+		return nil
+	}
+
+	select {
+	case ch <- msg:
+	default:
+	}
+
+	return nil
+}
+
+// subscribe to the reply topic on first use
+func (b *brokerReplies) subscribe(br broker.Broker) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.sub != nil {
+		return nil
+	}
+
+	topic := "go.micro.reply." + uuid.New().String()
+	sub, err := br.Subscribe(topic, b.handle)
+	if err != nil {
+		return err
+	}
+
+	b.topic = topic
+	b.sub = sub
+
+	return nil
+}
+
+func (b *brokerReplies) add(id string) chan *broker.Message {
+	ch := make(chan *broker.Message, 1)
+	b.Lock()
+	b.pending[id] = ch
+	b.Unlock()
+	return ch
+}
+
+func (b *brokerReplies) remove(id string) {
+	b.Lock()
+	delete(b.pending, id)
+	b.Unlock()
+}
+
+func (b *brokerClient) Send(m *transport.Message) error {
+	hdr := make(map[string]string, len(m.Header)+2)
+	for k, v := range m.Header {
+		hdr[k] = v
+	}
+	hdr["Micro-Reply-To"] = b.replies.topic
+	hdr["Micro-Correlation-Id"] = b.id
+
+	return b.broker.Publish(b.topic, &broker.Message{
+		Header: hdr,
+		Body:   m.Body,
+	})
+}
+
+func (b *brokerClient) Recv(m *transport.Message) error {
+	select {
+	case msg := <-b.rsp:
+		m.Header = msg.Header
+		m.Body = msg.Body
+		return nil
+	case <-b.exit:
+		return io.EOF
+	}
+}
+
+func (b *brokerClient) Close() error {
+	b.once.Do(func() {
+		close(b.exit)
+		b.replies.remove(b.id)
+	})
+	return nil
+}
+
+func (b *brokerClient) Local() string {
+	return b.replies.topic
+}
+
+func (b *brokerClient) Remote() string {
+	return b.topic
+}
+
+// callBroker makes the call over the broker rather than the transport. The
+// request is published to the service's call topic and the response is
+// received on the reply topic of the client.
+func (r *rpcClient) callBroker(ctx context.Context, req Request, resp interface{}, opts CallOptions) error {
+	if !r.once.Load().(bool) {
+		if err := r.opts.Broker.Connect(); err != nil {
+			return errors.InternalServerError("go.micro.client", err.Error())
+		}
+		r.once.Store(true)
+	}
+
+	if err := r.replies.subscribe(r.opts.Broker); err != nil {
+		return errors.InternalServerError("go.micro.client", "reply subscription error: %v", err)
+	}
+
+	msg := &transport.Message{
+		Header: make(map[string]string),
+	}
+
+	md, ok := metadata.FromContext(ctx)
+	if ok {
+		for k, v := range md {
+			// don't copy Micro-Topic header, that used for pub/sub
+			if k == "Micro-Topic" {
+				continue
+			}
+			msg.Header[k] = v
+		}
+	}
+
+	// set timeout in nanoseconds
+	msg.Header["Timeout"] = fmt.Sprintf("%d", opts.RequestTimeout)
+	// set the content type for the request
+	msg.Header["Content-Type"] = req.ContentType()
+	// set the accept header
+	msg.Header["Accept"] = req.ContentType()
+
+	cf, err := r.newCodec(req.ContentType())
+	if err != nil {
+		return errors.InternalServerError("go.micro.client", err.Error())
+	}
+
+	id := uuid.New().String()
+
+	c := &brokerClient{
+		broker:  r.opts.Broker,
+		replies: r.replies,
+		topic:   callTopic(req.Service()),
+		id:      id,
+		rsp:     r.replies.add(id),
+		exit:    make(chan bool),
+	}
+
+	seq := atomic.AddUint64(&r.seq, 1) - 1
+	codec := newRpcCodec(msg, c, cf, "")
+
+	stream := &rpcStream{
+		id:      fmt.Sprintf("%v", seq),
+		context: ctx,
+		request: req,
+		response: &rpcResponse{
+			socket: c,
+			codec:  codec,
+		},
+		codec:   codec,
+		closed:  make(chan bool),
+		release: func(err error) {},
+		sendEOS: false,
+	}
+	// close the stream on exiting this function
+	defer stream.Close()
+
+	// wait for error response
+	ch := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				ch <- errors.InternalServerError("go.micro.client", "panic recovered: %v", r)
+			}
+		}()
+
+		// send request
+		if err := stream.Send(req.Body()); err != nil {
+			ch <- err
+			return
+		}
+
+		// recv response
+		if err := stream.Recv(resp); err != nil {
+			ch <- err
+			return
+		}
+
+		// success
+		ch <- nil
+	}()
+
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+		grr := errors.Timeout("go.micro.client", fmt.Sprintf("%v", ctx.Err()))
+
+		// set the stream error
+		stream.Lock()
+		stream.err = grr
+		stream.Unlock()
+
+		return grr
+	}
+}
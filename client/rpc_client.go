@@ -26,6 +26,9 @@ type rpcClient struct {
 	opts Options
 	pool pool.Pool
 	seq  uint64
+
+	// responses to calls made over the broker
+	replies *brokerReplies
 }
 
 func newRpcClient(opt ...Option) Client {
@@ -41,6 +44,9 @@ func newRpcClient(opt ...Option) Client {
 		opts: opts,
 		pool: p,
 		seq:  0,
+		replies: &brokerReplies{
+			pending: make(map[string]chan *broker.Message),
+		},
 	}
 	rc.once.Store(false)
 
@@ -365,9 +371,22 @@ func (r *rpcClient) Call(ctx context.Context, request Request, response interfac
 		opt(&callOpts)
 	}
 
-	next, err := r.next(request, callOpts)
-	if err != nil {
-		return err
+	// make copy of call method
+	rcall := r.call
+
+	// calls over the broker are published to the service rather than a node
+	next := func() (*registry.Node, error) {
+		return &registry.Node{Id: "broker", Address: callTopic(request.Service())}, nil
+	}
+	if callOpts.BrokerCall {
+		rcall = func(ctx context.Context, _ *registry.Node, req Request, rsp interface{}, opts CallOptions) error {
+			return r.callBroker(ctx, req, rsp, opts)
+		}
+	} else {
+		var err error
+		if next, err = r.next(request, callOpts); err != nil {
+			return err
+		}
 	}
 
 	// check if we already have a deadline
@@ -391,9 +410,6 @@ func (r *rpcClient) Call(ctx context.Context, request Request, response interfac
 	default:
 	}
 
-	// make copy of call method
-	rcall := r.call
-
 	// wrap the call in reverse
 	for i := len(callOpts.CallWrappers); i > 0; i-- {
 		rcall = callOpts.CallWrappers[i-1](rcall)
@@ -424,7 +440,9 @@ func (r *rpcClient) Call(ctx context.Context, request Request, response interfac
 
 		// make the call
 		err = rcall(ctx, node, request, response, callOpts)
-		r.opts.Selector.Mark(service, node, err)
+		if !callOpts.BrokerCall {
+			r.opts.Selector.Mark(service, node, err)
+		}
 		return err
 	}
 
@@ -574,7 +592,10 @@ func (r *rpcClient) Publish(ctx context.Context, msg Message, opts ...PublishOpt
 		md = make(map[string]string)
 	}
 
-	id := uuid.New().String()
+	id := options.MessageId
+	if len(id) == 0 {
+		id = uuid.New().String()
+	}
 	md["Content-Type"] = msg.ContentType()
 	md["Micro-Topic"] = msg.Topic()
 	md["Micro-Id"] = id
@@ -627,7 +648,11 @@ func (r *rpcClient) Publish(ctx context.Context, msg Message, opts ...PublishOpt
 	return r.opts.Broker.Publish(topic, &broker.Message{
 		Header: md,
 		Body:   body,
-	}, broker.PublishContext(options.Context))
+	},
+		broker.PublishContext(options.Context),
+		broker.DeliverAt(options.DeliverAt),
+		broker.MessageId(id),
+	)
 }
 
 func (r *rpcClient) NewMessage(topic string, message interface{}, opts ...MessageOption) Message {
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"c-z.dev/go-micro/config/reader"
+	"c-z.dev/go-micro/config/schema"
+	"c-z.dev/go-micro/config/source"
+	"c-z.dev/go-micro/logger"
+	"c-z.dev/go-micro/util/backoff"
+)
+
+// Binding is a struct bound to a config path. The struct is
+// replaced on every valid change so it can be read without locking.
+type Binding struct {
+	typ    reflect.Type
+	schema schema.Schema
+	value  atomic.Value
+	w      Watcher
+	exit   chan bool
+
+	sync.RWMutex
+	callbacks map[string][]func(old, new interface{})
+}
+
+// Bind a struct to the config at path. The struct pointed to by v is set
+// to the current value, later values are read with Get. Missing values
+// are set from the struct's default tags and changes which fail the
+// struct's validate tags are ignored, see schema.FromStruct.
+func Bind(c Config, v interface{}, path ...string) (*Binding, error) {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("bind requires a pointer to a struct")
+	}
+
+	s, err := schema.FromStruct(v)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Binding{
+		typ:       t.Elem(),
+		schema:    s,
+		exit:      make(chan bool),
+		callbacks: make(map[string][]func(old, new interface{})),
+	}
+
+	// load the current value
+	nv, err := b.decode(c.Get(path...))
+	if err != nil {
+		return nil, err
+	}
+	reflect.ValueOf(v).Elem().Set(reflect.ValueOf(nv).Elem())
+	b.value.Store(nv)
+
+	w, err := c.Watch(path...)
+	if err != nil {
+		return nil, err
+	}
+	b.w = w
+
+	go b.run()
+
+	return b, nil
+}
+
+// decode the value into a new struct, applying defaults and validation
+func (b *Binding) decode(v reader.Value) (interface{}, error) {
+	var m map[string]interface{}
+	if err := v.Scan(&m); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		m = make(map[string]interface{})
+	}
+
+	b.schema.Defaults(m)
+	if err := b.schema.Validate(m); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	nv := reflect.New(b.typ).Interface()
+	if err := json.Unmarshal(data, nv); err != nil {
+		return nil, err
+	}
+
+	return nv, nil
+}
+
+func (b *Binding) run() {
+	var attempts int
+
+	for {
+		v, err := b.w.Next()
+		if err == source.ErrWatcherStopped {
+			return
+		}
+		if err != nil {
+			attempts++
+			if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+				logger.Debugf("config binding watch error: %v", err)
+			}
+			select {
+			case <-b.exit:
+				return
+			case <-time.After(backoff.Do(attempts)):
+			}
+			continue
+		}
+		attempts = 0
+
+		nv, err := b.decode(v)
+		if err != nil {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("config binding rejected change: %v", err)
+			}
+			continue
+		}
+
+		old := b.value.Load()
+		b.value.Store(nv)
+		b.notify(old, nv)
+	}
+}
+
+// notify calls the callbacks of the fields which changed
+func (b *Binding) notify(old, nv interface{}) {
+	b.RLock()
+	defer b.RUnlock()
+
+	for field, fns := range b.callbacks {
+		ov, ok := lookup(reflect.ValueOf(old), field)
+		if !ok {
+			continue
+		}
+		v, ok := lookup(reflect.ValueOf(nv), field)
+		if !ok {
+			continue
+		}
+		if reflect.DeepEqual(ov, v) {
+			continue
+		}
+		for _, fn := range fns {
+			fn(ov, v)
+		}
+	}
+}
+
+// lookup a field by its dot separated json name, an
+// empty field returns the whole struct
+func lookup(v reflect.Value, field string) (interface{}, bool) {
+	if len(field) == 0 {
+		return v.Elem().Interface(), true
+	}
+
+	for _, name := range strings.Split(field, ".") {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil, true
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return nil, false
+		}
+
+		var found bool
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("json"), ",")[0]
+			if tag == name || (len(tag) == 0 && strings.EqualFold(f.Name, name)) {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	return v.Interface(), true
+}
+
+// Get returns a pointer to the current struct. It's replaced
+// rather than updated on change and must not be modified.
+func (b *Binding) Get() interface{} {
+	return b.value.Load()
+}
+
+// OnChange calls fn with the old and new value when the field changes.
+// The field is the dot separated json name, an empty field is called
+// with the whole struct on any change.
+func (b *Binding) OnChange(field string, fn func(old, new interface{})) {
+	b.Lock()
+	b.callbacks[field] = append(b.callbacks[field], fn)
+	b.Unlock()
+}
+
+// Stop watching for changes
+func (b *Binding) Stop() error {
+	select {
+	case <-b.exit:
+		return nil
+	default:
+		close(b.exit)
+	}
+	return b.w.Stop()
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/config/reader"
+	"c-z.dev/go-micro/config/source"
+	"c-z.dev/go-micro/config/source/memory"
+)
+
+type bindConfig struct {
+	Host string `json:"host" default:"localhost"`
+	Port int    `json:"port" default:"8080" validate:"max=65535"`
+	DB   struct {
+		Name string `json:"name"`
+	} `json:"db"`
+}
+
+type errWatcher struct {
+	calls int32
+	err   error
+}
+
+func (w *errWatcher) Next() (reader.Value, error) {
+	atomic.AddInt32(&w.calls, 1)
+	return nil, w.err
+}
+
+func (w *errWatcher) Stop() error {
+	return nil
+}
+
+func TestBindWatchErrors(t *testing.T) {
+	// errors back off
+	w := &errWatcher{err: errors.New("unavailable")}
+	b := &Binding{w: w, exit: make(chan bool)}
+	go b.run()
+	time.Sleep(200 * time.Millisecond)
+	b.Stop()
+	if calls := atomic.LoadInt32(&w.calls); calls > 3 {
+		t.Fatalf("expected backoff between errors got %d calls", calls)
+	}
+
+	// a stopped watcher ends the binding
+	w = &errWatcher{err: source.ErrWatcherStopped}
+	b = &Binding{w: w, exit: make(chan bool)}
+	done := make(chan bool)
+	go func() {
+		b.run()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("binding did not stop with its watcher")
+	}
+	if calls := atomic.LoadInt32(&w.calls); calls != 1 {
+		t.Fatalf("expected 1 call got %d", calls)
+	}
+}
+
+func TestBind(t *testing.T) {
+	src := memory.NewSource(memory.WithJSON([]byte(`{"server": {"port": 9090, "db": {"name": "foo"}}}`)))
+	conf, err := NewConfig(WithSource(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conf.Close()
+
+	var cfg bindConfig
+	b, err := Bind(conf, &cfg, "server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Stop()
+
+	if cfg.Host != "localhost" || cfg.Port != 9090 || cfg.DB.Name != "foo" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	changes := make(chan [2]interface{}, 1)
+	b.OnChange("db.name", func(old, new interface{}) {
+		changes <- [2]interface{}{old, new}
+	})
+	b.OnChange("host", func(old, new interface{}) {
+		t.Errorf("unexpected change of host from %v to %v", old, new)
+	})
+
+	// wait for the source to be watched
+	time.Sleep(100 * time.Millisecond)
+
+	// invalid changes are ignored
+	src.Write(&source.ChangeSet{Data: []byte(`{"server": {"port": 100000, "db": {"name": "foo"}}}`), Format: "json"})
+	time.Sleep(100 * time.Millisecond)
+	if port := b.Get().(*bindConfig).Port; port != 9090 {
+		t.Fatalf("expected port 9090 got %d", port)
+	}
+
+	src.Write(&source.ChangeSet{Data: []byte(`{"server": {"port": 9091, "db": {"name": "bar"}}}`), Format: "json"})
+
+	select {
+	case c := <-changes:
+		if c[0] != "foo" || c[1] != "bar" {
+			t.Fatalf("expected change from foo to bar got %v", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for change")
+	}
+
+	if port := b.Get().(*bindConfig).Port; port != 9091 {
+		t.Fatalf("expected port 9091 got %d", port)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"c-z.dev/go-micro/config/loader"
 	"c-z.dev/go-micro/config/reader"
+	"c-z.dev/go-micro/config/schema"
 	"c-z.dev/go-micro/config/source"
 	"c-z.dev/go-micro/config/source/file"
 )
@@ -20,7 +21,8 @@ type Config interface {
 	Options() Options
 	// Close stop the config loader/watcher
 	Close() error
-	// Load config sources
+	// Load config sources. Later sources override earlier ones,
+	// use source.NewLayer to mount, prefix or prioritise a source.
 	Load(source ...source.Source) error
 	// Sync force a source change-set sync
 	Sync() error
@@ -28,7 +30,8 @@ type Config interface {
 	Watch(path ...string) (Watcher, error)
 }
 
-// Watcher is the config watcher
+// Watcher is the config watcher. Next returns a *schema.ValidationError
+// when a change is rejected by the schema and can be called again.
 type Watcher interface {
 	Next() (reader.Value, error)
 	Stop() error
@@ -38,6 +41,8 @@ type Options struct {
 	Loader loader.Loader
 	Reader reader.Reader
 	Source []source.Source
+	// Schema validates the config before changes are applied
+	Schema schema.Schema
 
 	// for alternative data
 	Context context.Context
@@ -48,7 +53,8 @@ type Option func(o *Options)
 // Default Config Manager
 var DefaultConfig, _ = NewConfig()
 
-// NewConfig returns new config
+// NewConfig returns new config. An error is returned if the values of
+// the sources fail validation against the schema.
 func NewConfig(opts ...Option) (Config, error) {
 	return newConfig(opts...)
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"errors"
 	"sync"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"c-z.dev/go-micro/config/loader/memory"
 	"c-z.dev/go-micro/config/reader"
 	"c-z.dev/go-micro/config/reader/json"
+	"c-z.dev/go-micro/config/schema"
 	"c-z.dev/go-micro/config/source"
 )
 
@@ -33,7 +35,11 @@ type watcher struct {
 func newConfig(opts ...Option) (Config, error) {
 	var c config
 
-	c.Init(opts...)
+	// only values which fail validation fail construction
+	var verr *schema.ValidationError
+	if err := c.Init(opts...); errors.As(err, &verr) {
+		return nil, err
+	}
 	go c.run()
 
 	return &c, nil
@@ -50,7 +56,10 @@ func (c *config) Init(opts ...Option) error {
 
 	// default loader uses the configured reader
 	if c.opts.Loader == nil {
-		c.opts.Loader = memory.NewLoader(memory.WithReader(c.opts.Reader))
+		c.opts.Loader = memory.NewLoader(
+			memory.WithReader(c.opts.Reader),
+			memory.WithSchema(c.opts.Schema),
+		)
 	}
 
 	err := c.opts.Loader.Load(c.opts.Source...)
@@ -80,6 +89,11 @@ func (c *config) run() {
 		for {
 			// get change-set
 			snap, err := w.Next()
+			// rejected change, keep the current values
+			var verr *schema.ValidationError
+			if errors.As(err, &verr) {
+				continue
+			}
 			if err != nil {
 				return err
 			}
@@ -91,11 +105,17 @@ func (c *config) run() {
 				continue
 			}
 
+			// set values, keeping the last good
+			// values if the change-set can't be read
+			vals, err := c.opts.Reader.Values(snap.ChangeSet)
+			if err != nil {
+				c.Unlock()
+				continue
+			}
+
 			// save
 			c.snap = snap
-
-			// set values
-			c.vals, _ = c.opts.Reader.Values(snap.ChangeSet)
+			c.vals = vals
 
 			c.Unlock()
 		}
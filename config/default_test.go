@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"c-z.dev/go-micro/config/schema"
 	"c-z.dev/go-micro/config/source"
 	"c-z.dev/go-micro/config/source/env"
 	"c-z.dev/go-micro/config/source/file"
@@ -74,6 +75,13 @@ func TestConfigLoadWithInvalidFile(t *testing.T) {
 		os.Remove(path)
 	}()
 
+	// sources which fail to load don't fail construction
+	if _, err := NewConfig(WithSource(file.NewSource(
+		file.WithPath("/i/do/not/exists.json"),
+	))); err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
 	// Create new config
 	conf, err := NewConfig()
 	if err != nil {
@@ -164,3 +172,68 @@ func TestConfigWatcherDirtyOverride(t *testing.T) {
 		equalS(t, conf.Get(k).String(""), v)
 	}
 }
+
+func TestConfigSchema(t *testing.T) {
+	type serverConfig struct {
+		Name string `json:"name" validate:"required"`
+		Port int    `json:"port" default:"8080" validate:"max=65535"`
+	}
+
+	s, err := schema.FromStruct(serverConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an invalid config is rejected on load
+	if _, err := NewConfig(
+		WithSchema(s),
+		WithSource(memory.NewSource(memory.WithJSON([]byte(`{"port": 80}`)))),
+	); err == nil {
+		t.Fatal("Expected error but none !")
+	}
+
+	src := memory.NewSource(memory.WithJSON([]byte(`{"name": "foo"}`)))
+	conf, err := NewConfig(WithSchema(s), WithSource(src))
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if port := conf.Get("port").Int(0); port != 8080 {
+		t.Fatalf("Expected default port 8080 but got %d", port)
+	}
+
+	w, err := conf.Watch("name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// wait for the source to be watched
+	time.Sleep(100 * time.Millisecond)
+
+	// invalid changes are reported to the watcher
+	src.Write(&source.ChangeSet{Data: []byte(`{"name": "bar", "port": 100000}`), Format: "json"})
+	_, err = w.Next()
+	if _, ok := err.(*schema.ValidationError); !ok {
+		t.Fatalf("Expected validation error but got %v", err)
+	}
+	if name := conf.Get("name").String(""); name != "foo" {
+		t.Fatalf("Expected last good value foo but got %s", name)
+	}
+
+	// malformed changes aren't reported as rejected, the source is watched again
+	src.Write(&source.ChangeSet{Data: []byte(`{"name": `), Format: "json"})
+	time.Sleep(1200 * time.Millisecond)
+	if name := conf.Get("name").String(""); name != "foo" {
+		t.Fatalf("Expected last good value foo but got %s", name)
+	}
+
+	// valid changes are applied
+	src.Write(&source.ChangeSet{Data: []byte(`{"name": "bar"}`), Format: "json"})
+	v, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := v.String(""); name != "bar" {
+		t.Fatalf("Expected bar but got %s", name)
+	}
+}
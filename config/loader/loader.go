@@ -3,8 +3,10 @@ package loader
 
 import (
 	"context"
+	"errors"
 
 	"c-z.dev/go-micro/config/reader"
+	"c-z.dev/go-micro/config/schema"
 	"c-z.dev/go-micro/config/source"
 )
 
@@ -28,12 +30,43 @@ type Loader interface {
 type Watcher interface {
 	// First call to next may return the current Snapshot
 	// If you are watching a path then only the data from
-	// that path is returned.
+	// that path is returned. A change-set rejected by the
+	// schema returns a *schema.ValidationError after which
+	// the watcher can continue to be used.
 	Next() (*Snapshot, error)
 	// Stop watching for changes
 	Stop() error
 }
 
+// History is implemented by loaders which retain previous snapshots
+type History interface {
+	// History returns the retained snapshots, oldest first.
+	// The change-set source is the source which changed.
+	History() []*Snapshot
+	// Diff returns the values changed between two versions
+	Diff(from, to string) ([]*Change, error)
+	// Rollback restores the snapshot with the given version.
+	// It applies until a source next changes.
+	Rollback(version string) error
+}
+
+// Change is a value which differs between two snapshots
+type Change struct {
+	// Path to the value
+	Path []string
+	// Action is create, update or delete
+	Action string
+	// The previous value
+	From interface{}
+	// The new value
+	To interface{}
+}
+
+var (
+	// ErrVersionNotFound is returned when a snapshot is not in the history
+	ErrVersionNotFound = errors.New("version not found")
+)
+
 // Snapshot is a merged ChangeSet
 type Snapshot struct {
 	// The merged ChangeSet
@@ -45,6 +78,8 @@ type Snapshot struct {
 type Options struct {
 	Reader reader.Reader
 	Source []source.Source
+	// Schema validates each merged snapshot before it's applied
+	Schema schema.Schema
 
 	// for alternative data
 	Context context.Context
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"reflect"
+	"sort"
+
+	"c-z.dev/go-micro/config/loader"
+)
+
+// diff compares two decoded values and returns the changes at each path
+func diff(path []string, from, to interface{}) []*loader.Change {
+	fm, fok := from.(map[string]interface{})
+	tm, tok := to.(map[string]interface{})
+
+	// compare nested objects key by key
+	if (fok || from == nil) && (tok || to == nil) && (fok || tok) {
+		keys := make(map[string]bool)
+		for k := range fm {
+			keys[k] = true
+		}
+		for k := range tm {
+			keys[k] = true
+		}
+
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		var changes []*loader.Change
+		for _, k := range sorted {
+			p := append(append([]string{}, path...), k)
+			fv, fok := fm[k]
+			tv, tok := tm[k]
+
+			switch {
+			case !fok:
+				changes = append(changes, &loader.Change{Path: p, Action: "create", To: tv})
+			case !tok:
+				changes = append(changes, &loader.Change{Path: p, Action: "delete", From: fv})
+			default:
+				changes = append(changes, diff(p, fv, tv)...)
+			}
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+
+	return []*loader.Change{{Path: path, Action: "update", From: from, To: to}}
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	ejson "encoding/json"
+	"fmt"
+	"sort"
+
+	"c-z.dev/go-micro/config/source"
+)
+
+// layer applies the layer options of the sources to their sets
+// and returns the sets in the order they should be merged
+func (m *memory) layer(sources []source.Source, sets []*source.ChangeSet) ([]*source.ChangeSet, error) {
+	type layered struct {
+		set      *source.ChangeSet
+		priority int
+	}
+
+	var layers []layered
+
+	for i, set := range sets {
+		var opts source.LayerOptions
+		if i < len(sources) {
+			if l, ok := sources[i].(source.Layer); ok {
+				opts = l.LayerOptions()
+			}
+		}
+
+		if set != nil && (len(opts.Prefix) > 0 || len(opts.Mount) > 0) {
+			ls, err := m.mount(set, opts)
+			if err != nil {
+				return nil, err
+			}
+			set = ls
+		}
+
+		layers = append(layers, layered{set, opts.Priority})
+	}
+
+	// later sources win between equal priorities
+	sort.SliceStable(layers, func(i, j int) bool {
+		return layers[i].priority < layers[j].priority
+	})
+
+	ordered := make([]*source.ChangeSet, 0, len(layers))
+	for _, l := range layers {
+		ordered = append(ordered, l.set)
+	}
+
+	return ordered, nil
+}
+
+// mount restricts the set to its prefix and mounts it at the path
+func (m *memory) mount(set *source.ChangeSet, opts source.LayerOptions) (*source.ChangeSet, error) {
+	// decode the set whatever its format
+	cs, err := m.opts.Reader.Merge(set)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if len(cs.Data) > 0 {
+		if err := ejson.Unmarshal(cs.Data, &v); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, k := range opts.Prefix {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			v = nil
+			break
+		}
+		v = vm[k]
+	}
+
+	if v == nil {
+		v = map[string]interface{}{}
+	}
+
+	for i := len(opts.Mount) - 1; i >= 0; i-- {
+		v = map[string]interface{}{opts.Mount[i]: v}
+	}
+
+	// the root must be an object to merge
+	if _, ok := v.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("source %s can't be mounted at the root", set.Source)
+	}
+
+	b, err := ejson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	ls := &source.ChangeSet{
+		Data:      b,
+		Format:    cs.Format,
+		Source:    set.Source,
+		Timestamp: set.Timestamp,
+	}
+	ls.Checksum = ls.Sum()
+
+	return ls, nil
+}
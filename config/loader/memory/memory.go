@@ -3,6 +3,7 @@ package memory
 import (
 	"bytes"
 	"container/list"
+	ejson "encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"c-z.dev/go-micro/config/loader"
 	"c-z.dev/go-micro/config/reader"
 	"c-z.dev/go-micro/config/reader/json"
+	"c-z.dev/go-micro/config/schema"
 	"c-z.dev/go-micro/config/source"
 )
 
@@ -30,11 +32,24 @@ type memory struct {
 	sources []source.Source
 
 	watchers *list.List
+
+	// previous snapshots, oldest first
+	history []*loader.Snapshot
+	// the number of snapshots retained
+	historySize int
+	// options of the reference expansion
+	expand []reader.ExpandOption
 }
 
+var (
+	// DefaultHistory is the number of snapshots retained by default
+	DefaultHistory = 10
+)
+
 type updateValue struct {
 	version string
 	value   reader.Value
+	err     error
 }
 
 type watcher struct {
@@ -59,21 +74,32 @@ func (m *memory) watch(idx int, s source.Source) {
 			m.Lock()
 
 			// save
+			prev := m.sets[idx]
 			m.sets[idx] = cs
 
-			// merge sets
-			set, err := m.opts.Reader.Merge(m.sets...)
+			// merge and validate sets
+			set, vals, err := m.apply(m.sources, m.sets)
 			if err != nil {
+				// keep the last good snapshot
+				m.sets[idx] = prev
 				m.Unlock()
-				return err
+
+				// report invalid changes, any other error restarts the watch
+				var verr *schema.ValidationError
+				if !errors.As(err, &verr) {
+					return err
+				}
+				m.reject(err)
+				continue
 			}
 
 			// set values
-			m.vals, _ = m.opts.Reader.Values(set)
+			m.vals = vals
 			m.snap = &loader.Snapshot{
 				ChangeSet: set,
 				Version:   genVer(),
 			}
+			m.record(m.sources[idx].String())
 			m.Unlock()
 
 			// send watch updates
@@ -129,22 +155,23 @@ func (m *memory) loaded() bool {
 }
 
 // reload reads the sets and creates new values
-func (m *memory) reload() error {
+func (m *memory) reload(src string) error {
 	m.Lock()
 
-	// merge sets
-	set, err := m.opts.Reader.Merge(m.sets...)
+	// merge and validate sets
+	set, vals, err := m.apply(m.sources, m.sets)
 	if err != nil {
 		m.Unlock()
 		return err
 	}
 
 	// set values
-	m.vals, _ = m.opts.Reader.Values(set)
+	m.vals = vals
 	m.snap = &loader.Snapshot{
 		ChangeSet: set,
 		Version:   genVer(),
 	}
+	m.record(src)
 
 	m.Unlock()
 
@@ -154,6 +181,84 @@ func (m *memory) reload() error {
 	return nil
 }
 
+// apply merges the sets of the sources, evaluates their references, fills in the
+// schema defaults and validates the result. It returns the change-set and values
+// to be applied. References are only evaluated here, the snapshot holds the result
+// so escaped references stay literal when it's read again.
+func (m *memory) apply(sources []source.Source, sets []*source.ChangeSet) (*source.ChangeSet, reader.Values, error) {
+	sets, err := m.layer(sources, sets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	set, err := m.opts.Reader.Merge(sets...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := make(map[string]interface{})
+	if len(set.Data) > 0 {
+		if err := ejson.Unmarshal(set.Data, &data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ev, err := reader.Expand(data, m.expand...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to expand config: %w", err)
+	}
+	data = ev.(map[string]interface{})
+
+	// defaults are part of the change-set so they survive a snapshot
+	if m.opts.Schema != nil {
+		m.opts.Schema.Defaults(data)
+	}
+
+	b, err := ejson.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cs := &source.ChangeSet{
+		Data:      b,
+		Format:    set.Format,
+		Source:    set.Source,
+		Timestamp: set.Timestamp,
+	}
+	cs.Checksum = cs.Sum()
+
+	vals, err := m.opts.Reader.Values(cs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// validate the values as they will be read
+	if m.opts.Schema != nil {
+		if err := m.opts.Schema.Validate(vals.Map()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return cs, vals, nil
+}
+
+// reject reports a change-set which failed validation to the watchers
+func (m *memory) reject(err error) {
+	m.RLock()
+	watchers := make([]*watcher, 0, m.watchers.Len())
+	for e := m.watchers.Front(); e != nil; e = e.Next() {
+		watchers = append(watchers, e.Value.(*watcher))
+	}
+	m.RUnlock()
+
+	for _, w := range watchers {
+		select {
+		case w.updates <- updateValue{err: err}:
+		default:
+		}
+	}
+}
+
 func (m *memory) update() {
 	watchers := make([]*watcher, 0, m.watchers.Len())
 
@@ -219,20 +324,14 @@ func (m *memory) Sync() error {
 		ch, err := source.Read()
 		if err != nil {
 			gerr = append(gerr, err.Error())
-			continue
+			// keep the sets in line with the sources
+			ch = nil
 		}
 		sets = append(sets, ch)
 	}
 
-	// merge sets
-	set, err := m.opts.Reader.Merge(sets...)
-	if err != nil {
-		m.Unlock()
-		return err
-	}
-
-	// set values
-	vals, err := m.opts.Reader.Values(set)
+	// merge and validate sets
+	set, vals, err := m.apply(m.sources, sets)
 	if err != nil {
 		m.Unlock()
 		return err
@@ -242,6 +341,7 @@ func (m *memory) Sync() error {
 		ChangeSet: set,
 		Version:   genVer(),
 	}
+	m.record("sync")
 
 	m.Unlock()
 
@@ -305,6 +405,10 @@ func (m *memory) Get(path ...string) (reader.Value, error) {
 
 func (m *memory) Load(sources ...source.Source) error {
 	var gerrors []string
+	//nolint:prealloc
+	var loaded []source.Source
+	//nolint:prealloc
+	var sets []*source.ChangeSet
 
 	for _, source := range sources {
 		set, err := source.Read()
@@ -316,15 +420,34 @@ func (m *memory) Load(sources ...source.Source) error {
 			// continue processing
 			continue
 		}
-		m.Lock()
+		loaded = append(loaded, source)
+		sets = append(sets, set)
+	}
+
+	m.Lock()
+	// don't add sources which make the config invalid
+	if m.opts.Schema != nil {
+		all := append(append([]*source.ChangeSet{}, m.sets...), sets...)
+		srcs := append(append([]source.Source{}, m.sources...), loaded...)
+		if _, _, err := m.apply(srcs, all); err != nil {
+			m.Unlock()
+			if len(gerrors) == 0 {
+				return err
+			}
+			gerrors = append(gerrors, err.Error())
+			return errors.New(strings.Join(gerrors, "\n"))
+		}
+	}
+	names := make([]string, 0, len(loaded))
+	for i, source := range loaded {
 		m.sources = append(m.sources, source)
-		m.sets = append(m.sets, set)
-		idx := len(m.sets) - 1
-		m.Unlock()
-		go m.watch(idx, source)
+		m.sets = append(m.sets, sets[i])
+		names = append(names, source.String())
+		go m.watch(len(m.sets)-1, source)
 	}
+	m.Unlock()
 
-	if err := m.reload(); err != nil {
+	if err := m.reload(strings.Join(names, ",")); err != nil {
 		gerrors = append(gerrors, err.Error())
 	}
 
@@ -366,6 +489,113 @@ func (m *memory) Watch(path ...string) (loader.Watcher, error) {
 	return w, nil
 }
 
+// record adds the current snapshot to the history if it changed.
+// The caller must hold the lock.
+func (m *memory) record(src string) {
+	if m.historySize <= 0 {
+		return
+	}
+
+	if l := len(m.history); l > 0 && m.history[l-1].ChangeSet.Checksum == m.snap.ChangeSet.Checksum {
+		return
+	}
+
+	snap := loader.Copy(m.snap)
+	snap.ChangeSet.Source = src
+	m.history = append(m.history, snap)
+
+	if l := len(m.history); l > m.historySize {
+		m.history = m.history[l-m.historySize:]
+	}
+}
+
+// snapshot returns the snapshot with the version from the history
+func (m *memory) snapshot(version string) (*loader.Snapshot, error) {
+	for _, snap := range m.history {
+		if snap.Version == version {
+			return snap, nil
+		}
+	}
+	return nil, loader.ErrVersionNotFound
+}
+
+// History returns the retained snapshots, oldest first
+func (m *memory) History() []*loader.Snapshot {
+	m.RLock()
+	defer m.RUnlock()
+
+	history := make([]*loader.Snapshot, 0, len(m.history))
+	for _, snap := range m.history {
+		history = append(history, loader.Copy(snap))
+	}
+	return history
+}
+
+// Diff returns the values changed between two versions in the history
+func (m *memory) Diff(from, to string) ([]*loader.Change, error) {
+	m.RLock()
+	a, err := m.snapshot(from)
+	if err != nil {
+		m.RUnlock()
+		return nil, err
+	}
+	b, err := m.snapshot(to)
+	if err != nil {
+		m.RUnlock()
+		return nil, err
+	}
+	m.RUnlock()
+
+	var av, bv map[string]interface{}
+	if len(a.ChangeSet.Data) > 0 {
+		if err := ejson.Unmarshal(a.ChangeSet.Data, &av); err != nil {
+			return nil, err
+		}
+	}
+	if len(b.ChangeSet.Data) > 0 {
+		if err := ejson.Unmarshal(b.ChangeSet.Data, &bv); err != nil {
+			return nil, err
+		}
+	}
+
+	return diff(nil, av, bv), nil
+}
+
+// Rollback restores a snapshot from the history. It's applied as
+// a new version so watchers are updated with the previous values.
+func (m *memory) Rollback(version string) error {
+	m.Lock()
+
+	snap, err := m.snapshot(version)
+	if err != nil {
+		m.Unlock()
+		return err
+	}
+
+	cs := *snap.ChangeSet
+	cs.Source = m.snap.ChangeSet.Source
+
+	vals, err := m.opts.Reader.Values(&cs)
+	if err != nil {
+		m.Unlock()
+		return err
+	}
+
+	m.vals = vals
+	m.snap = &loader.Snapshot{
+		ChangeSet: &cs,
+		Version:   genVer(),
+	}
+	m.record("rollback")
+
+	m.Unlock()
+
+	// update watchers
+	m.update()
+
+	return nil
+}
+
 func (m *memory) String() string {
 	return "memory"
 }
@@ -391,9 +621,12 @@ func (w *watcher) Next() (*loader.Snapshot, error) {
 	for {
 		select {
 		case <-w.exit:
-			return nil, errors.New("watcher stopped")
+			return nil, source.ErrWatcherStopped
 
 		case uv := <-w.updates:
+			if uv.err != nil {
+				return nil, uv.err
+			}
 			if uv.version <= w.version {
 				continue
 			}
@@ -415,8 +648,8 @@ func (w *watcher) Stop() error {
 	select {
 	case <-w.exit:
 	default:
+		// updates isn't closed as the loader may still be sending
 		close(w.exit)
-		close(w.updates)
 	}
 
 	return nil
@@ -436,10 +669,20 @@ func NewLoader(opts ...loader.Option) loader.Loader {
 	}
 
 	m := &memory{
-		exit:     make(chan bool),
-		opts:     options,
-		watchers: list.New(),
-		sources:  options.Source,
+		exit:        make(chan bool),
+		opts:        options,
+		watchers:    list.New(),
+		sources:     options.Source,
+		historySize: DefaultHistory,
+	}
+
+	if options.Context != nil {
+		if size, ok := options.Context.Value(historyKey{}).(int); ok {
+			m.historySize = size
+		}
+		if ok, _ := options.Context.Value(fileReferencesKey{}).(bool); ok {
+			m.expand = append(m.expand, reader.ExpandFiles())
+		}
 	}
 
 	m.sets = make([]*source.ChangeSet, len(options.Source))
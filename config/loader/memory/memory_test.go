@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/config/loader"
+	"c-z.dev/go-micro/config/reader/json"
+	"c-z.dev/go-micro/config/source"
+	msource "c-z.dev/go-micro/config/source/memory"
+)
+
+func TestHistory(t *testing.T) {
+	src := msource.NewSource(msource.WithJSON([]byte(`{"db": {"host": "a", "port": 1}}`)))
+
+	l := NewLoader(WithHistory(2))
+	defer l.Close()
+
+	if err := l.Load(src); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := l.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// wait for the source to be watched
+	time.Sleep(100 * time.Millisecond)
+
+	src.Write(&source.ChangeSet{Data: []byte(`{"db": {"host": "b", "user": "u"}}`), Format: "json"})
+	if _, err := w.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	h := l.(loader.History)
+	history := h.History()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 snapshots got %d", len(history))
+	}
+	if src := history[1].ChangeSet.Source; src != "memory" {
+		t.Fatalf("expected source memory got %s", src)
+	}
+
+	changes, err := h.Diff(history[0].Version, history[1].Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{
+		"db.host": "update",
+		"db.port": "delete",
+		"db.user": "create",
+	}
+	if len(changes) != len(expect) {
+		t.Fatalf("expected %d changes got %d", len(expect), len(changes))
+	}
+	for _, c := range changes {
+		path := c.Path[0] + "." + c.Path[1]
+		if expect[path] != c.Action {
+			t.Fatalf("expected %s to %s got %s", path, expect[path], c.Action)
+		}
+	}
+
+	// roll back to the first snapshot
+	if err := h.Rollback(history[0].Version); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := l.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.ChangeSet.Checksum != history[0].ChangeSet.Checksum {
+		t.Fatal("expected the first snapshot to be restored")
+	}
+
+	// the history is bounded
+	history = h.History()
+	if len(history) != 2 || history[1].ChangeSet.Source != "rollback" {
+		t.Fatalf("unexpected history %v", history)
+	}
+
+	if err := h.Rollback("unknown"); err != loader.ErrVersionNotFound {
+		t.Fatalf("expected version not found got %v", err)
+	}
+}
+
+func TestLayers(t *testing.T) {
+	defaults := msource.NewSource(msource.WithJSON([]byte(`{"db": {"host": "localhost", "port": 5432}, "level": "info"}`)))
+	// values outside the prefix are ignored
+	env := msource.NewSource(msource.WithJSON([]byte(`{"myapp": {"db": {"host": "env"}}, "level": "debug"}`)))
+	remote := msource.NewSource(msource.WithJSON([]byte(`{"host": "remote", "level": "error"}`)))
+
+	l := NewLoader()
+	defer l.Close()
+
+	if err := l.Load(
+		// the highest priority wins though loaded first
+		source.NewLayer(env, source.Prefix("myapp"), source.Priority(10)),
+		defaults,
+		source.NewLayer(remote, source.Mount("remote")),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := l.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vals, err := json.NewReader().Values(snap.ChangeSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		path  []string
+		value string
+	}{
+		{[]string{"db", "host"}, "env"},
+		{[]string{"db", "port"}, "5432"},
+		{[]string{"level"}, "info"},
+		{[]string{"remote", "host"}, "remote"},
+		{[]string{"remote", "level"}, "error"},
+		{[]string{"myapp"}, "null"},
+	}
+
+	for _, d := range testData {
+		if v := string(vals.Get(d.path...).Bytes()); v != d.value {
+			t.Fatalf("expected %s at %v got %s", d.value, d.path, v)
+		}
+	}
+}
+
+func TestLayersWithoutPriority(t *testing.T) {
+	defaults := msource.NewSource(msource.WithJSON([]byte(`{"host": "localhost"}`)))
+	env := msource.NewSource(msource.WithJSON([]byte(`{"myapp": {"level": "debug"}, "level": "info"}`)))
+	remote := msource.NewSource(msource.WithJSON([]byte(`{"host": "remote"}`)))
+
+	l := NewLoader()
+	defer l.Close()
+
+	if err := l.Load(
+		defaults,
+		source.NewLayer(env, source.Prefix("myapp")),
+		source.NewLayer(remote, source.Mount("remote")),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := l.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vals, err := json.NewReader().Values(snap.ChangeSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		path  []string
+		value string
+	}{
+		{[]string{"host"}, "localhost"},
+		{[]string{"level"}, "debug"},
+		{[]string{"remote", "host"}, "remote"},
+		{[]string{"myapp"}, "null"},
+	}
+
+	for _, d := range testData {
+		if v := vals.Get(d.path...).String("null"); v != d.value {
+			t.Fatalf("expected %s at %v got %s", d.value, d.path, v)
+		}
+	}
+}
+
+func TestReferences(t *testing.T) {
+	defaults := msource.NewSource(msource.WithJSON([]byte(`{"db": {"host": "localhost", "port": 5432}, "dsn": "postgres://${db.host}:${db.port}", "literal": "$${db.host}"}`)))
+	overrides := msource.NewSource(msource.WithJSON([]byte(`{"db": {"host": "10.0.0.1"}}`)))
+
+	l := NewLoader()
+	defer l.Close()
+
+	if err := l.Load(defaults, overrides); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := l.Watch("dsn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	check := func(dsn string) {
+		snap, err := l.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// snapshots are read again by the config and its watchers
+		vals, err := json.NewReader().Values(snap.ChangeSet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := vals.Get("dsn").String(""); v != dsn {
+			t.Fatalf("Expected %s got %s", dsn, v)
+		}
+		if v := vals.Get("literal").String(""); v != "${db.host}" {
+			t.Fatalf("Expected the escaped reference to be literal got %s", v)
+		}
+	}
+
+	// references are evaluated after the sources are merged
+	check("postgres://10.0.0.1:5432")
+
+	// wait for the sources to be watched
+	time.Sleep(100 * time.Millisecond)
+
+	// and only once after a source changes
+	overrides.Write(&source.ChangeSet{Data: []byte(`{"db": {"host": "10.0.0.2"}}`), Format: "json"})
+	if _, err := w.Next(); err != nil {
+		t.Fatal(err)
+	}
+	check("postgres://10.0.0.2:5432")
+}
@@ -1,11 +1,18 @@
 package memory
 
 import (
+	"context"
+
 	"c-z.dev/go-micro/config/loader"
 	"c-z.dev/go-micro/config/reader"
+	"c-z.dev/go-micro/config/schema"
 	"c-z.dev/go-micro/config/source"
 )
 
+type historyKey struct{}
+
+type fileReferencesKey struct{}
+
 // WithSource appends a source to list of sources
 func WithSource(s source.Source) loader.Option {
 	return func(o *loader.Options) {
@@ -19,3 +26,31 @@ func WithReader(r reader.Reader) loader.Option {
 		o.Reader = r
 	}
 }
+
+// WithSchema validates merged snapshots and fills in their defaults
+func WithSchema(s schema.Schema) loader.Option {
+	return func(o *loader.Options) {
+		o.Schema = s
+	}
+}
+
+// WithHistory sets the number of snapshots retained in the history
+func WithHistory(size int) loader.Option {
+	return func(o *loader.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, historyKey{}, size)
+	}
+}
+
+// WithFileReferences allows ${file:/path} references in the config to read
+// local files. Only enable it when every source of the config is trusted.
+func WithFileReferences() loader.Option {
+	return func(o *loader.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, fileReferencesKey{}, true)
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"c-z.dev/go-micro/config/loader"
 	"c-z.dev/go-micro/config/reader"
+	"c-z.dev/go-micro/config/schema"
 	"c-z.dev/go-micro/config/source"
 )
 
@@ -26,3 +27,11 @@ func WithReader(r reader.Reader) Option {
 		o.Reader = r
 	}
 }
+
+// WithSchema validates the config and fills in defaults. Changes
+// which fail validation are rejected and the last good config is kept.
+func WithSchema(s schema.Schema) Option {
+	return func(o *Options) {
+		o.Schema = s
+	}
+}
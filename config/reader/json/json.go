@@ -67,7 +67,7 @@ func (j *jsonReader) Values(ch *source.ChangeSet) (reader.Values, error) {
 	if ch.Format != "json" {
 		return nil, errors.New("unsupported format")
 	}
-	return newValues(ch)
+	return newValues(ch, reader.WithSecrets(j.opts.Secrets, j.opts.DecryptOptions...))
 }
 
 func (j *jsonReader) String() string {
@@ -3,6 +3,9 @@ package json
 import (
 	"testing"
 
+	"c-z.dev/go-micro/config/reader"
+	"c-z.dev/go-micro/config/secrets"
+	"c-z.dev/go-micro/config/secrets/secretbox"
 	"c-z.dev/go-micro/config/source"
 )
 
@@ -41,3 +44,71 @@ func TestReader(t *testing.T) {
 		}
 	}
 }
+
+func TestReaderSecrets(t *testing.T) {
+	key := make([]byte, 32)
+	copy(key, "a very secret key for the config")
+
+	s := secretbox.NewSecrets()
+	if err := s.Init(secrets.Key(key)); err != nil {
+		t.Fatal(err)
+	}
+
+	password, err := secrets.Seal(s, []byte("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := secrets.Seal(s, []byte("abc123"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(`{"db": {"user": "admin", "password": "` + password + `"}, "tokens": ["` + token + `"]}`)
+
+	r := NewReader(reader.WithSecrets(s))
+
+	c, err := r.Merge(&source.ChangeSet{Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := r.Values(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := values.Get("db", "password").String(""); v != "hunter2" {
+		t.Fatalf("Expected hunter2 got %s", v)
+	}
+	if v := values.Get("db", "user").String(""); v != "admin" {
+		t.Fatalf("Expected admin got %s", v)
+	}
+
+	var conf struct {
+		Tokens []string `json:"tokens"`
+	}
+	if err := values.Scan(&conf); err != nil {
+		t.Fatal(err)
+	}
+	if len(conf.Tokens) != 1 || conf.Tokens[0] != "abc123" {
+		t.Fatalf("Expected [abc123] got %v", conf.Tokens)
+	}
+
+	// without secrets the ciphertext is returned as is
+	values, err = NewReader().Values(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := values.Get("db", "password").String(""); v != password {
+		t.Fatalf("Expected ciphertext got %s", v)
+	}
+
+	// values which can't be decrypted are an error
+	other := secretbox.NewSecrets()
+	if err := other.Init(secrets.Key(make([]byte, 32))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewReader(reader.WithSecrets(other)).Values(c); err == nil {
+		t.Fatal("Expected error decrypting with the wrong key")
+	}
+}
@@ -22,12 +22,23 @@ type jsonValue struct {
 	*simple.Json
 }
 
-func newValues(ch *source.ChangeSet) (reader.Values, error) {
+func newValues(ch *source.ChangeSet, opts ...reader.Option) (reader.Values, error) {
+	options := reader.NewOptions(opts...)
+
 	sj := simple.New()
-	data, _ := reader.ReplaceEnvVars(ch.Data)
-	if err := sj.UnmarshalJSON(data); err != nil {
+	if err := sj.UnmarshalJSON(ch.Data); err != nil {
 		sj.SetPath(nil, string(ch.Data))
 	}
+
+	// decrypt sealed values
+	if options.Secrets != nil {
+		v, err := reader.DecryptValues(sj.Interface(), options.Secrets, options.DecryptOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt config: %w", err)
+		}
+		sj.SetPath(nil, v)
+	}
+
 	return &jsonValues{ch, sj}, nil
 }
 
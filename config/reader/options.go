@@ -4,14 +4,26 @@ import (
 	"c-z.dev/go-micro/config/encoder"
 	"c-z.dev/go-micro/config/encoder/json"
 	"c-z.dev/go-micro/config/encoder/xml"
+	"c-z.dev/go-micro/config/secrets"
 )
 
 type Options struct {
 	Encoding map[string]encoder.Encoder
+	// Secrets decrypts values sealed in an envelope
+	Secrets        secrets.Secrets
+	DecryptOptions []secrets.DecryptOption
 }
 
 type Option func(o *Options)
 
+// ExpandOptions are the references Expand may evaluate
+type ExpandOptions struct {
+	// Files allows ${file:...} references to read local files
+	Files bool
+}
+
+type ExpandOption func(o *ExpandOptions)
+
 func NewOptions(opts ...Option) Options {
 	options := Options{
 		Encoding: map[string]encoder.Encoder{
@@ -33,3 +45,20 @@ func WithEncoder(e encoder.Encoder) Option {
 		o.Encoding[e.String()] = e
 	}
 }
+
+// WithSecrets decrypts any value sealed with secrets.Seal when the
+// values are read, so sources can hold encrypted values
+func WithSecrets(s secrets.Secrets, opts ...secrets.DecryptOption) Option {
+	return func(o *Options) {
+		o.Secrets = s
+		o.DecryptOptions = opts
+	}
+}
+
+// ExpandFiles allows ${file:...} references to read local files. Only
+// enable it when every source of the config is trusted.
+func ExpandFiles() ExpandOption {
+	return func(o *ExpandOptions) {
+		o.Files = true
+	}
+}
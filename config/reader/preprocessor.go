@@ -5,6 +5,10 @@ import (
 	"regexp"
 )
 
+// ReplaceEnvVars replaces ${NAME} with the value of the environment variable.
+//
+// Deprecated: readers no longer replace environment variables, the loader
+// evaluates ${...} references with Expand.
 func ReplaceEnvVars(raw []byte) ([]byte, error) {
 	re := regexp.MustCompile(`\$\{([A-Za-z0-9_]+)\}`)
 	if re.Match(raw) {
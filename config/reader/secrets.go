@@ -0,0 +1,40 @@
+package reader
+
+import (
+	"fmt"
+
+	"c-z.dev/go-micro/config/secrets"
+)
+
+// DecryptValues walks the decoded values and replaces any string
+// sealed in an envelope with its decrypted value
+func DecryptValues(v interface{}, s secrets.Secrets, opts ...secrets.DecryptOption) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		if !secrets.IsEnvelope(t) {
+			return t, nil
+		}
+		b, err := secrets.Open(s, t, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case map[string]interface{}:
+		for k, val := range t {
+			dv, err := DecryptValues(val, s, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			t[k] = dv
+		}
+	case []interface{}:
+		for i, val := range t {
+			dv, err := DecryptValues(val, s, opts...)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			t[i] = dv
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,223 @@
+package reader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"c-z.dev/go-micro/config/secrets"
+)
+
+// Expand evaluates the ${...} references in the string values of the
+// merged config so layered sources can reuse values. A reference is
+//
+//	${db.host}           the value at a config path
+//	${PORT}              an environment variable, or a top level config value
+//	${env:PORT}          an environment variable
+//	${file:/run/secret}  the contents of a file, if enabled with ExpandFiles
+//	${PORT:-8080}        any of the above with a default if unset or empty
+//
+// A string which is a single reference to a config path keeps the type
+// of the value. $${...} is left as the literal ${...}.
+//
+// References are evaluated before sealed values are decrypted, so a sealed
+// value can only be referenced on its own and is rejected inside a larger
+// string rather than interpolated as ciphertext.
+func Expand(v interface{}, opts ...ExpandOption) (interface{}, error) {
+	e := &expander{
+		root:      v,
+		resolving: make(map[string]bool),
+	}
+	for _, o := range opts {
+		o(&e.opts)
+	}
+	return e.value(v)
+}
+
+type expander struct {
+	opts      ExpandOptions
+	root      interface{}
+	resolving map[string]bool
+}
+
+func (e *expander) value(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		return e.string(t)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			ev, err := e.value(val)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			m[k] = ev
+		}
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			ev, err := e.value(val)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			s[i] = ev
+		}
+		return s, nil
+	}
+	return v, nil
+}
+
+func (e *expander) string(s string) (interface{}, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	// a single reference keeps its type
+	if strings.HasPrefix(s, "${") && strings.Index(s, "}") == len(s)-1 {
+		return e.ref(s[2 : len(s)-1])
+	}
+
+	var b strings.Builder
+
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			break
+		}
+
+		// escaped
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1])
+			b.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+
+		j := strings.Index(s[i:], "}")
+		if j < 0 {
+			break
+		}
+
+		v, err := e.ref(s[i+2 : i+j])
+		if err != nil {
+			return nil, err
+		}
+		if sv, ok := v.(string); ok && secrets.IsEnvelope(sv) {
+			return nil, fmt.Errorf("sealed value can't be interpolated at ${%s}", s[i+2:i+j])
+		}
+
+		b.WriteString(s[:i])
+		b.WriteString(toString(v))
+		s = s[i+j+1:]
+	}
+
+	b.WriteString(s)
+
+	return b.String(), nil
+}
+
+// ref evaluates a single reference
+func (e *expander) ref(expr string) (interface{}, error) {
+	name, def, hasDef := expr, "", false
+	if i := strings.Index(expr, ":-"); i >= 0 {
+		name, def, hasDef = expr[:i], expr[i+2:], true
+	}
+
+	var v interface{}
+	var found bool
+
+	switch {
+	case strings.HasPrefix(name, "file:"):
+		if !e.opts.Files {
+			return nil, fmt.Errorf("file references are not enabled at ${%s}", name)
+		}
+		b, err := ioutil.ReadFile(strings.TrimPrefix(name, "file:"))
+		if err != nil && !hasDef {
+			return nil, err
+		}
+		if err == nil {
+			v, found = strings.TrimRight(string(b), "\r\n"), true
+		}
+	case strings.HasPrefix(name, "env:"):
+		v, found = os.LookupEnv(strings.TrimPrefix(name, "env:"))
+	case strings.Contains(name, "."):
+		var err error
+		v, found, err = e.path(name)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		if env, ok := os.LookupEnv(name); ok {
+			v, found = env, true
+			break
+		}
+		var err error
+		v, found, err = e.path(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if hasDef && (!found || v == nil || v == "") {
+		return def, nil
+	}
+	if !found {
+		return "", nil
+	}
+
+	return v, nil
+}
+
+// path looks up and expands the value at a dot separated config path
+func (e *expander) path(name string) (interface{}, bool, error) {
+	v := e.root
+
+	for _, k := range strings.Split(name, ".") {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			val, ok := t[k]
+			if !ok {
+				return nil, false, nil
+			}
+			v = val
+		case []interface{}:
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, false, nil
+			}
+			v = t[i]
+		default:
+			return nil, false, nil
+		}
+	}
+
+	if e.resolving[name] {
+		return nil, false, fmt.Errorf("reference cycle at ${%s}", name)
+	}
+	e.resolving[name] = true
+	defer delete(e.resolving, name)
+
+	ev, err := e.value(v)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return ev, true, nil
+}
+
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(t)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,93 @@
+package reader
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	os.Setenv("EXPAND_HOST", "10.0.0.1")
+	os.Unsetenv("EXPAND_UNSET")
+
+	secret := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(secret, []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	data := `{
+		"db": {"host": "${EXPAND_HOST}", "port": 5432, "password": "${file:` + secret + `}"},
+		"dsn": "postgres://${db.host}:${db.port}",
+		"port": "${db.port}",
+		"listen": "${EXPAND_UNSET:-:8080}",
+		"name": "${env:EXPAND_UNSET}",
+		"missing": "${file:/does/not/exist:-none}",
+		"escaped": "$${db.host}",
+		"hosts": ["${db.host}"],
+		"replica": "${hosts.0}"
+	}`
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	ev, err := Expand(v, ExpandFiles())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]interface{}{
+		"db":      map[string]interface{}{"host": "10.0.0.1", "port": float64(5432), "password": "s3cret"},
+		"dsn":     "postgres://10.0.0.1:5432",
+		"port":    float64(5432),
+		"listen":  ":8080",
+		"name":    "",
+		"missing": "none",
+		"escaped": "${db.host}",
+		"hosts":   []interface{}{"10.0.0.1"},
+		"replica": "10.0.0.1",
+	}
+	if !reflect.DeepEqual(ev, expect) {
+		t.Fatalf("Expected %v got %v", expect, ev)
+	}
+
+	// the input is unchanged
+	if v.(map[string]interface{})["dsn"] != "postgres://${db.host}:${db.port}" {
+		t.Fatal("Expected the input to be unchanged")
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	testData := []struct {
+		data  string
+		error string
+	}{
+		{`{"a": {"b": "${c.d}"}, "c": {"d": "${a.b}"}}`, "reference cycle"},
+		{`{"a": "${file:/does/not/exist}"}`, "no such file"},
+		{`{"a": "enc:abc", "b": "pass=${a}"}`, "sealed value"},
+	}
+
+	// files are only read when enabled
+	var v interface{}
+	if err := json.Unmarshal([]byte(`{"a": "${file:/etc/hostname}"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Expand(v); err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Fatalf("Expected file references to be disabled got %v", err)
+	}
+
+	for _, d := range testData {
+		var v interface{}
+		if err := json.Unmarshal([]byte(d.data), &v); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Expand(v, ExpandFiles())
+		if err == nil || !strings.Contains(err.Error(), d.error) {
+			t.Fatalf("Expected %q got %v", d.error, err)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Package schema validates config values and fills in their defaults
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema validates merged config values before they are applied
+type Schema interface {
+	// Defaults sets missing values to their default
+	Defaults(map[string]interface{})
+	// Validate the values, returning a *ValidationError
+	// which describes every invalid value
+	Validate(map[string]interface{}) error
+	// String returns the schema as a JSON Schema document
+	String() string
+}
+
+// ValidationError describes the values which are invalid
+type ValidationError struct {
+	Errors []string
+}
+
+func (v *ValidationError) Error() string {
+	return "invalid config: " + strings.Join(v.Errors, "; ")
+}
+
+// Node is the subset of JSON Schema which is supported
+type Node struct {
+	Type                 string           `json:"type,omitempty"`
+	Description          string           `json:"description,omitempty"`
+	Properties           map[string]*Node `json:"properties,omitempty"`
+	Required             []string         `json:"required,omitempty"`
+	AdditionalProperties *bool            `json:"additionalProperties,omitempty"`
+	Items                *Node            `json:"items,omitempty"`
+	Enum                 []interface{}    `json:"enum,omitempty"`
+	Minimum              *float64         `json:"minimum,omitempty"`
+	Maximum              *float64         `json:"maximum,omitempty"`
+	MinLength            *int             `json:"minLength,omitempty"`
+	MaxLength            *int             `json:"maxLength,omitempty"`
+	Pattern              string           `json:"pattern,omitempty"`
+	Default              interface{}      `json:"default,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+type schema struct {
+	root *Node
+}
+
+// NewSchema parses a JSON Schema document. The type, properties, required,
+// additionalProperties, items, enum, minimum, maximum, minLength, maxLength,
+// pattern and default keywords are supported.
+func NewSchema(b []byte) (Schema, error) {
+	var root *Node
+	if err := json.Unmarshal(b, &root); err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("schema is empty")
+	}
+	if err := root.compile(); err != nil {
+		return nil, err
+	}
+	return &schema{root: root}, nil
+}
+
+func (n *Node) compile() error {
+	if len(n.Pattern) > 0 {
+		re, err := regexp.Compile(n.Pattern)
+		if err != nil {
+			return err
+		}
+		n.pattern = re
+	}
+	for _, p := range n.Properties {
+		if err := p.compile(); err != nil {
+			return err
+		}
+	}
+	if n.Items != nil {
+		return n.Items.compile()
+	}
+	return nil
+}
+
+func (s *schema) Defaults(v map[string]interface{}) {
+	s.root.defaults(v)
+}
+
+func (s *schema) Validate(v map[string]interface{}) error {
+	var errs []string
+	s.root.validate("", v, &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (s *schema) String() string {
+	b, _ := json.Marshal(s.root)
+	return string(b)
+}
+
+// copyValue makes a deep copy of a default so it isn't shared
+func copyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[k] = copyValue(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = copyValue(val)
+		}
+		return s
+	}
+	return v
+}
+
+// defaults fills in the object's missing properties and returns
+// whether any value was set
+func (n *Node) defaults(v interface{}) bool {
+	var set bool
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, p := range n.Properties {
+			val, ok := t[k]
+			if !ok {
+				if p.Default != nil {
+					t[k] = copyValue(p.Default)
+					set = true
+					continue
+				}
+				// create nested objects which hold defaults
+				if p.Type == "object" {
+					m := make(map[string]interface{})
+					if p.defaults(m) {
+						t[k] = m
+						set = true
+					}
+				}
+				continue
+			}
+			if p.defaults(val) {
+				set = true
+			}
+		}
+	case []interface{}:
+		if n.Items == nil {
+			return false
+		}
+		for _, val := range t {
+			if n.Items.defaults(val) {
+				set = true
+			}
+		}
+	}
+
+	return set
+}
+
+func join(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+func typeOf(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if t == math.Trunc(t) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func (n *Node) validate(path string, v interface{}, errs *[]string) {
+	name := path
+	if len(name) == 0 {
+		name = "config"
+	}
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, name+": "+fmt.Sprintf(format, args...))
+	}
+
+	// values decoded with UseNumber
+	if num, ok := v.(json.Number); ok {
+		f, err := num.Float64()
+		if err != nil {
+			fail("invalid number %s", num)
+			return
+		}
+		v = f
+	}
+
+	typ := typeOf(v)
+	if len(n.Type) > 0 && n.Type != typ && !(n.Type == "number" && typ == "integer") {
+		fail("expected %s got %s", n.Type, typ)
+		return
+	}
+
+	if len(n.Enum) > 0 {
+		var found bool
+		for _, e := range n.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %v", n.Enum)
+		}
+	}
+
+	switch t := v.(type) {
+	case float64:
+		if n.Minimum != nil && t < *n.Minimum {
+			fail("%v is less than the minimum %v", t, *n.Minimum)
+		}
+		if n.Maximum != nil && t > *n.Maximum {
+			fail("%v is greater than the maximum %v", t, *n.Maximum)
+		}
+	case string:
+		if n.MinLength != nil && len(t) < *n.MinLength {
+			fail("length %d is less than the minimum %d", len(t), *n.MinLength)
+		}
+		if n.MaxLength != nil && len(t) > *n.MaxLength {
+			fail("length %d is greater than the maximum %d", len(t), *n.MaxLength)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(t) {
+			fail("does not match %s", n.Pattern)
+		}
+	case []interface{}:
+		if n.Items != nil {
+			for i, val := range t {
+				n.Items.validate(fmt.Sprintf("%s[%d]", path, i), val, errs)
+			}
+		}
+	case map[string]interface{}:
+		for _, k := range n.Required {
+			if _, ok := t[k]; !ok {
+				*errs = append(*errs, join(path, k)+": is required")
+			}
+		}
+
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			p, ok := n.Properties[k]
+			if !ok {
+				if n.AdditionalProperties != nil && !*n.AdditionalProperties {
+					*errs = append(*errs, join(path, k)+": is not allowed")
+				}
+				continue
+			}
+			p.validate(join(path, k), t[k], errs)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Host    string        `json:"host" validate:"required"`
+	Port    int           `json:"port" default:"8080" validate:"min=1,max=65535"`
+	Level   string        `json:"level" default:"info" validate:"oneof=debug info error"`
+	Timeout time.Duration `json:"timeout" default:"5s"`
+	DB      struct {
+		Name  string   `json:"name" default:"micro"`
+		Hosts []string `json:"hosts" default:"a,b"`
+	} `json:"db"`
+}
+
+func TestFromStruct(t *testing.T) {
+	s, err := FromStruct(testConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := map[string]interface{}{"host": "localhost"}
+	s.Defaults(v)
+
+	if v["port"] != float64(8080) {
+		t.Fatalf("expected default port got %v", v["port"])
+	}
+	if v["timeout"] != float64(5*time.Second) {
+		t.Fatalf("expected default timeout got %v", v["timeout"])
+	}
+	db, ok := v["db"].(map[string]interface{})
+	if !ok || db["name"] != "micro" || len(db["hosts"].([]interface{})) != 2 {
+		t.Fatalf("expected nested defaults got %v", v["db"])
+	}
+	if err := s.Validate(v); err != nil {
+		t.Fatal(err)
+	}
+
+	v = map[string]interface{}{"port": float64(70000), "level": "trace"}
+	err = s.Validate(v)
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected validation error got %v", err)
+	}
+	if len(verr.Errors) != 3 {
+		t.Fatalf("expected 3 errors got %v", verr.Errors)
+	}
+	for _, field := range []string{"host", "port", "level"} {
+		if !strings.Contains(err.Error(), field+":") {
+			t.Fatalf("expected error for %s got %v", field, err)
+		}
+	}
+}
+
+func TestFromStructPattern(t *testing.T) {
+	s, err := FromStruct(struct {
+		Code string `json:"code" validate:"required, pattern=^[a-z]{1,3}$"`
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Validate(map[string]interface{}{"code": "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(map[string]interface{}{"code": "abcd"}); err == nil {
+		t.Fatal("expected the pattern to be validated")
+	}
+	if err := s.Validate(map[string]interface{}{}); err == nil {
+		t.Fatal("expected the code to be required")
+	}
+}
+
+func TestNewSchema(t *testing.T) {
+	s, err := NewSchema([]byte(`{
+		"type": "object",
+		"required": ["name"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "pattern": "^[a-z]+$"},
+			"replicas": {"type": "integer", "minimum": 1, "default": 3},
+			"tags": {"type": "array", "items": {"type": "string", "maxLength": 3}}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := map[string]interface{}{"name": "api", "tags": []interface{}{"a", "b"}}
+	s.Defaults(v)
+	if v["replicas"] != float64(3) {
+		t.Fatalf("expected default replicas got %v", v["replicas"])
+	}
+	if err := s.Validate(v); err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		value map[string]interface{}
+		error string
+	}{
+		{map[string]interface{}{"name": "API"}, "name: does not match"},
+		{map[string]interface{}{"name": "api", "replicas": 1.5}, "replicas: expected integer got number"},
+		{map[string]interface{}{"name": "api", "tags": []interface{}{"long"}}, "tags[0]: length 4"},
+		{map[string]interface{}{"name": "api", "foo": "bar"}, "foo: is not allowed"},
+	}
+
+	for _, d := range testData {
+		err := s.Validate(d.value)
+		if err == nil || !strings.Contains(err.Error(), d.error) {
+			t.Fatalf("expected %q got %v", d.error, err)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// FromStruct derives a schema from the fields of a Go struct. Field names
+// are taken from the json tag, defaults from the default tag and rules from
+// the validate tag e.g.
+//
+//	type Config struct {
+//		Port    int           `json:"port" default:"8080" validate:"min=1,max=65535"`
+//		Level   string        `json:"level" default:"info" validate:"oneof=debug info error"`
+//		Host    string        `json:"host" validate:"required"`
+//		Timeout time.Duration `json:"timeout" default:"5s"`
+//	}
+//
+// The supported rules are required, min, max, minlen, maxlen, pattern and oneof.
+// A pattern takes the rest of the tag so it must be the last rule.
+// Durations are stored as nanoseconds so they can be scanned back into the struct.
+func FromStruct(v interface{}) (Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema: %T is not a struct", v)
+	}
+	root, err := structNode(t)
+	if err != nil {
+		return nil, err
+	}
+	if err := root.compile(); err != nil {
+		return nil, err
+	}
+	return &schema{root: root}, nil
+}
+
+func structNode(t reflect.Type) (*Node, error) {
+	n := &Node{
+		Type:       "object",
+		Properties: make(map[string]*Node),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if len(f.PkgPath) > 0 {
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if len(parts[0]) > 0 {
+				name = parts[0]
+			}
+		}
+
+		p, err := typeNode(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("schema: field %s: %v", f.Name, err)
+		}
+
+		required, err := p.rules(f.Tag.Get("validate"))
+		if err != nil {
+			return nil, fmt.Errorf("schema: field %s: %v", f.Name, err)
+		}
+		if required {
+			n.Required = append(n.Required, name)
+		}
+
+		if def, ok := f.Tag.Lookup("default"); ok {
+			v, err := parseDefault(f.Type, def)
+			if err != nil {
+				return nil, fmt.Errorf("schema: field %s: invalid default %q: %v", f.Name, def, err)
+			}
+			p.Default = v
+		}
+
+		n.Properties[name] = p
+	}
+
+	return n, nil
+}
+
+func typeNode(t reflect.Type) (*Node, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		return &Node{Type: "integer"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Node{Type: "string"}, nil
+	case reflect.Bool:
+		return &Node{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Node{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		min := float64(0)
+		return &Node{Type: "integer", Minimum: &min}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Node{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := typeNode(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Node{Type: "array", Items: items}, nil
+	case reflect.Map:
+		return &Node{Type: "object"}, nil
+	case reflect.Struct:
+		return structNode(t)
+	case reflect.Interface:
+		return &Node{}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// rules applies the validate tag to the node and returns whether the field is required
+func (n *Node) rules(tag string) (bool, error) {
+	var required bool
+
+	if len(tag) == 0 {
+		return false, nil
+	}
+
+	// the pattern may contain commas so it's the last rule
+	var pattern string
+	if i := strings.Index(tag, "pattern="); i >= 0 {
+		if prev := strings.TrimSpace(tag[:i]); len(prev) == 0 || strings.HasSuffix(prev, ",") {
+			tag, pattern = strings.TrimSuffix(prev, ","), tag[i:]
+		}
+	}
+
+	var rules []string
+	if len(tag) > 0 {
+		rules = strings.Split(tag, ",")
+	}
+	if len(pattern) > 0 {
+		rules = append(rules, pattern)
+	}
+
+	for _, rule := range rules {
+		parts := strings.SplitN(rule, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		var val string
+		if len(parts) == 2 {
+			val = parts[1]
+		}
+
+		switch key {
+		case "required":
+			required = true
+		case "min", "max":
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid rule %s: %v", rule, err)
+			}
+			if key == "min" {
+				n.Minimum = &f
+			} else {
+				n.Maximum = &f
+			}
+		case "minlen", "maxlen":
+			i, err := strconv.Atoi(val)
+			if err != nil {
+				return false, fmt.Errorf("invalid rule %s: %v", rule, err)
+			}
+			if key == "minlen" {
+				n.MinLength = &i
+			} else {
+				n.MaxLength = &i
+			}
+		case "pattern":
+			if _, err := regexp.Compile(val); err != nil {
+				return false, fmt.Errorf("invalid rule %s: %v", rule, err)
+			}
+			n.Pattern = val
+		case "oneof":
+			for _, o := range strings.Fields(val) {
+				var e interface{} = o
+				if n.Type == "integer" || n.Type == "number" {
+					f, err := strconv.ParseFloat(o, 64)
+					if err != nil {
+						return false, fmt.Errorf("invalid rule %s: %v", rule, err)
+					}
+					e = f
+				}
+				n.Enum = append(n.Enum, e)
+			}
+		default:
+			return false, fmt.Errorf("unknown rule %s", rule)
+		}
+	}
+
+	return required, nil
+}
+
+// parseDefault converts the default tag into a value as it would be decoded from JSON
+func parseDefault(t reflect.Type, s string) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, err
+		}
+		return float64(d), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return s, nil
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		return float64(i), err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(s, 10, 64)
+		return float64(i), err
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+	case reflect.Slice, reflect.Array:
+		var vals []interface{}
+		for _, p := range strings.Split(s, ",") {
+			v, err := parseDefault(t.Elem(), strings.TrimSpace(p))
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+		}
+		return vals, nil
+	}
+
+	return nil, fmt.Errorf("defaults are not supported for %s", t)
+}
@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// EnvelopePrefix marks an encrypted config value
+const EnvelopePrefix = "enc:"
+
+var (
+	// ErrNotEnvelope is returned when opening a value without the envelope prefix
+	ErrNotEnvelope = errors.New("value is not an encrypted envelope")
+)
+
+// IsEnvelope reports whether the value is an encrypted envelope
+func IsEnvelope(v string) bool {
+	return strings.HasPrefix(v, EnvelopePrefix)
+}
+
+// Seal encrypts the value and returns it as an envelope which can be
+// placed in any config source, e.g. enc:<base64 ciphertext>
+func Seal(s Secrets, v []byte, opts ...EncryptOption) (string, error) {
+	b, err := s.Encrypt(v, opts...)
+	if err != nil {
+		return "", err
+	}
+	return EnvelopePrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Open decrypts an envelope created by Seal
+func Open(s Secrets, v string, opts ...DecryptOption) ([]byte, error) {
+	if !IsEnvelope(v) {
+		return nil, ErrNotEnvelope
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, EnvelopePrefix))
+	if err != nil {
+		return nil, err
+	}
+	return s.Decrypt(b, opts...)
+}
@@ -0,0 +1,131 @@
+// Package keyring is a config/secrets implementation which embeds the key id
+// in each ciphertext so keys can be rotated without re-encrypting everything
+package keyring
+
+import (
+	"errors"
+	"fmt"
+
+	"c-z.dev/go-micro/config/secrets"
+	"c-z.dev/go-micro/config/secrets/secretbox"
+)
+
+// version of the ciphertext header
+const version = 1
+
+var (
+	// ErrKeyNotFound is returned when decrypting a value encrypted with an unknown key
+	ErrKeyNotFound = errors.New("key not found in keyring")
+)
+
+type keyring struct {
+	options secrets.Options
+
+	// encrypt is the key id used to encrypt
+	encrypt string
+	keys    map[string]secrets.Secrets
+}
+
+// NewSecrets returns a keyring. The Key and KeyID options set the key used to
+// encrypt while DecryptKey adds keys which can still decrypt e.g.
+//
+//	keyring.NewSecrets(
+//		secrets.KeyID("2020-06"),
+//		secrets.Key(newKey),
+//		secrets.DecryptKey("2020-01", oldKey),
+//	)
+func NewSecrets(opts ...secrets.Option) secrets.Secrets {
+	k := &keyring{}
+	for _, o := range opts {
+		o(&k.options)
+	}
+	return k
+}
+
+func (k *keyring) Init(opts ...secrets.Option) error {
+	for _, o := range opts {
+		o(&k.options)
+	}
+	if len(k.options.KeyID) == 0 {
+		return errors.New("no key id is defined")
+	}
+	if len(k.options.KeyID) > 255 {
+		return errors.New("key id must be at most 255 bytes long")
+	}
+
+	newSecrets := secretbox.NewSecrets
+	if k.options.Context != nil {
+		if fn, ok := k.options.Context.Value(cipherKey{}).(func(...secrets.Option) secrets.Secrets); ok && fn != nil {
+			newSecrets = fn
+		}
+	}
+
+	keys := make(map[string]secrets.Secrets, len(k.options.Keys)+1)
+	for id, key := range k.options.Keys {
+		s := newSecrets()
+		if err := s.Init(secrets.Key(key)); err != nil {
+			return fmt.Errorf("key %s: %w", id, err)
+		}
+		keys[id] = s
+	}
+
+	s := newSecrets()
+	if err := s.Init(secrets.Key(k.options.Key)); err != nil {
+		return fmt.Errorf("key %s: %w", k.options.KeyID, err)
+	}
+	keys[k.options.KeyID] = s
+
+	k.encrypt = k.options.KeyID
+	k.keys = keys
+	return nil
+}
+
+func (k *keyring) Options() secrets.Options {
+	return k.options
+}
+
+func (k *keyring) String() string {
+	return "keyring"
+}
+
+// Encrypt with the current key, the ciphertext is prefixed with
+// the version and key id as [version][len(id)][id][ciphertext]
+func (k *keyring) Encrypt(in []byte, opts ...secrets.EncryptOption) ([]byte, error) {
+	s, ok := k.keys[k.encrypt]
+	if !ok {
+		return nil, errors.New("keyring is not initialised")
+	}
+	b, err := s.Encrypt(in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, 2+len(k.encrypt)+len(b))
+	out = append(out, version, byte(len(k.encrypt)))
+	out = append(out, k.encrypt...)
+	return append(out, b...), nil
+}
+
+// Decrypt with the key the value was encrypted with
+func (k *keyring) Decrypt(in []byte, opts ...secrets.DecryptOption) ([]byte, error) {
+	id, b, err := KeyOf(in)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+	}
+	return s.Decrypt(b, opts...)
+}
+
+// KeyOf returns the key id a value was encrypted with and the remaining ciphertext
+func KeyOf(in []byte) (string, []byte, error) {
+	if len(in) < 2 || in[0] != version {
+		return "", nil, errors.New("invalid keyring ciphertext")
+	}
+	n := int(in[1])
+	if len(in) < 2+n {
+		return "", nil, errors.New("invalid keyring ciphertext")
+	}
+	return string(in[2 : 2+n]), in[2+n:], nil
+}
@@ -0,0 +1,62 @@
+package keyring
+
+import (
+	"errors"
+	"testing"
+
+	"c-z.dev/go-micro/config/secrets"
+)
+
+func TestKeyring(t *testing.T) {
+	oldKey := make([]byte, 32)
+	copy(oldKey, "the old key for the config value")
+	newKey := make([]byte, 32)
+	copy(newKey, "the new key for the config value")
+
+	if err := NewSecrets(secrets.Key(oldKey)).Init(); err == nil {
+		t.Fatal("Expected error without a key id")
+	}
+
+	old := NewSecrets(secrets.KeyID("v1"), secrets.Key(oldKey))
+	if err := old.Init(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := old.Encrypt([]byte("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _, err := KeyOf(b); err != nil || id != "v1" {
+		t.Fatalf("Expected key id v1 got %s %v", id, err)
+	}
+
+	// rotate, the old key can still decrypt
+	s := NewSecrets(
+		secrets.KeyID("v2"),
+		secrets.Key(newKey),
+		secrets.DecryptKey("v1", oldKey),
+	)
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := s.Decrypt(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v) != "hunter2" {
+		t.Fatalf("Expected hunter2 got %s", v)
+	}
+
+	b, err = s.Encrypt([]byte("abc123"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _, _ := KeyOf(b); id != "v2" {
+		t.Fatalf("Expected key id v2 got %s", id)
+	}
+
+	// the old keyring doesn't know the new key
+	if _, err := old.Decrypt(b); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Expected ErrKeyNotFound got %v", err)
+	}
+}
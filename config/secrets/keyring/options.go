@@ -0,0 +1,19 @@
+package keyring
+
+import (
+	"context"
+
+	"c-z.dev/go-micro/config/secrets"
+)
+
+type cipherKey struct{}
+
+// Cipher sets the secrets implementation used for each key, it defaults to secretbox
+func Cipher(fn func(...secrets.Option) secrets.Secrets) secrets.Option {
+	return func(o *secrets.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, cipherKey{}, fn)
+	}
+}
@@ -0,0 +1,150 @@
+// Package file is a kms provider which reads master keys from local files
+package file
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"c-z.dev/go-micro/config/secrets/kms"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const keyLength = 32
+
+var (
+	// DefaultPath is the directory the key files are read from
+	DefaultPath = "keys"
+	// ErrKeyNotFound is returned when there's no file for a key id
+	ErrKeyNotFound = errors.New("master key not found")
+)
+
+// Options for the file provider
+type Options struct {
+	// Path is the directory holding the key files
+	Path string
+	// KeyID of the master key new data keys are wrapped with
+	KeyID string
+}
+
+// Option sets options
+type Option func(o *Options)
+
+// WithPath sets the directory holding the key files
+func WithPath(p string) Option {
+	return func(o *Options) {
+		o.Path = p
+	}
+}
+
+// WithKeyID sets the master key new data keys are wrapped with
+func WithKeyID(id string) Option {
+	return func(o *Options) {
+		o.KeyID = id
+	}
+}
+
+type provider struct {
+	opts Options
+}
+
+// NewProvider returns a provider reading master keys from <path>/<id>.key.
+// Each file holds a 32 byte key, raw or base64 encoded. Keys are read on use
+// so a rotated key only needs to be written and set with WithKeyID while the
+// old files are kept to unwrap existing values.
+func NewProvider(opts ...Option) kms.Provider {
+	options := Options{
+		Path: DefaultPath,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &provider{opts: options}
+}
+
+func validID(id string) error {
+	if len(id) == 0 || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return fmt.Errorf("invalid key id %q", id)
+	}
+	return nil
+}
+
+func (p *provider) key(id string) (*[keyLength]byte, error) {
+	if err := validID(id); err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(filepath.Join(p.opts.Path, id+".key"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if len(b) != keyLength {
+		d, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+		if err != nil || len(d) != keyLength {
+			return nil, fmt.Errorf("master key %s must be %d bytes long", id, keyLength)
+		}
+		b = d
+	}
+
+	var key [keyLength]byte
+	copy(key[:], b)
+	return &key, nil
+}
+
+func (p *provider) Wrap(dataKey []byte) (string, []byte, error) {
+	key, err := p.key(p.opts.KeyID)
+	if err != nil {
+		return "", nil, err
+	}
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", nil, err
+	}
+	return p.opts.KeyID, secretbox.Seal(nonce[:], dataKey, &nonce, key), nil
+}
+
+func (p *provider) Unwrap(id string, wrapped []byte) ([]byte, error) {
+	key, err := p.key(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < 24 {
+		return nil, errors.New("invalid wrapped key")
+	}
+	var nonce [24]byte
+	copy(nonce[:], wrapped[:24])
+	dataKey, ok := secretbox.Open(nil, wrapped[24:], &nonce, key)
+	if !ok {
+		return nil, fmt.Errorf("couldn't unwrap key with master key %s", id)
+	}
+	return dataKey, nil
+}
+
+func (p *provider) String() string {
+	return "file"
+}
+
+// GenerateKey writes a new random master key to <path>/<id>.key
+func GenerateKey(path, id string) error {
+	if err := validID(id); err != nil {
+		return err
+	}
+	key := make([]byte, keyLength)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return err
+	}
+	data := []byte(base64.StdEncoding.EncodeToString(key) + "\n")
+	return ioutil.WriteFile(filepath.Join(path, id+".key"), data, 0600)
+}
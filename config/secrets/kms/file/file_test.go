@@ -0,0 +1,66 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"c-z.dev/go-micro/config/secrets"
+	"c-z.dev/go-micro/config/secrets/kms"
+)
+
+func TestProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := GenerateKey(dir, "v1"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := kms.NewSecrets(kms.WithProvider(NewProvider(WithPath(dir), WithKeyID("v1"))))
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := secrets.Seal(s, []byte("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rotate the master key, values wrapped with v1 can still be opened
+	if err := GenerateKey(dir, "v2"); err != nil {
+		t.Fatal(err)
+	}
+	s = kms.NewSecrets(kms.WithProvider(NewProvider(WithPath(dir), WithKeyID("v2"))))
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := secrets.Open(s, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hunter2" {
+		t.Fatalf("Expected hunter2 got %s", b)
+	}
+
+	c, err := s.Encrypt([]byte("abc123"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := kms.KeyOf(c); id != "v2" {
+		t.Fatalf("Expected master key v2 got %s", id)
+	}
+
+	// once the old master key is removed its values can't be opened
+	os.Remove(dir + "/v1.key")
+	if _, err := secrets.Open(s, v); err == nil {
+		t.Fatal("Expected error opening value without its master key")
+	}
+	if _, err := s.Decrypt(c); err != nil {
+		t.Fatal(err)
+	}
+}
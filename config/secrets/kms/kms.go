@@ -0,0 +1,170 @@
+// Package kms is a config/secrets implementation using envelope encryption.
+// Each value is encrypted with a random data key which is in turn wrapped by
+// a master key held by a Provider, so master keys never leave the provider
+// and can be rotated independently of the data.
+package kms
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"c-z.dev/go-micro/config/secrets"
+	"c-z.dev/go-micro/config/secrets/secretbox"
+)
+
+// version of the ciphertext header
+const version = 1
+
+// length of the generated data keys
+const dataKeyLength = 32
+
+// Provider holds the master keys used to wrap data keys
+type Provider interface {
+	// Wrap encrypts the data key with the current master key
+	// and returns the id of the master key used
+	Wrap(key []byte) (id string, wrapped []byte, err error)
+	// Unwrap decrypts a data key wrapped with the master key id
+	Unwrap(id string, wrapped []byte) ([]byte, error)
+	// Provider implementation
+	String() string
+}
+
+type providerKey struct{}
+
+// WithProvider sets the master key provider
+func WithProvider(p Provider) secrets.Option {
+	return func(o *secrets.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, providerKey{}, p)
+	}
+}
+
+type kms struct {
+	options  secrets.Options
+	provider Provider
+}
+
+// NewSecrets returns an envelope encryption codec, the provider must be set with WithProvider
+func NewSecrets(opts ...secrets.Option) secrets.Secrets {
+	k := &kms{}
+	for _, o := range opts {
+		o(&k.options)
+	}
+	return k
+}
+
+func (k *kms) Init(opts ...secrets.Option) error {
+	for _, o := range opts {
+		o(&k.options)
+	}
+	if k.options.Context == nil {
+		return errors.New("no key provider is defined")
+	}
+	p, ok := k.options.Context.Value(providerKey{}).(Provider)
+	if !ok || p == nil {
+		return errors.New("no key provider is defined")
+	}
+	k.provider = p
+	return nil
+}
+
+func (k *kms) Options() secrets.Options {
+	return k.options
+}
+
+func (k *kms) String() string {
+	return "kms"
+}
+
+// Encrypt with a new data key. The ciphertext is laid out as
+// [version][len(id)][id][len(wrapped key) uint16][wrapped key][ciphertext]
+func (k *kms) Encrypt(in []byte, opts ...secrets.EncryptOption) ([]byte, error) {
+	if k.provider == nil {
+		return nil, errors.New("kms is not initialised")
+	}
+
+	key := make([]byte, dataKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("couldn't generate a data key: %w", err)
+	}
+
+	id, wrapped, err := k.provider.Wrap(key)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't wrap data key: %w", err)
+	}
+	if len(id) > 255 || len(wrapped) > 65535 {
+		return nil, errors.New("wrapped data key is too long")
+	}
+
+	s := secretbox.NewSecrets()
+	if err := s.Init(secrets.Key(key)); err != nil {
+		return nil, err
+	}
+	b, err := s.Encrypt(in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 4+len(id)+len(wrapped)+len(b))
+	out = append(out, version, byte(len(id)))
+	out = append(out, id...)
+	out = append(out, 0, 0)
+	binary.BigEndian.PutUint16(out[len(out)-2:], uint16(len(wrapped)))
+	out = append(out, wrapped...)
+	return append(out, b...), nil
+}
+
+// Decrypt unwraps the data key with the provider and decrypts the value
+func (k *kms) Decrypt(in []byte, opts ...secrets.DecryptOption) ([]byte, error) {
+	if k.provider == nil {
+		return nil, errors.New("kms is not initialised")
+	}
+
+	id, wrapped, b, err := parse(in)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := k.provider.Unwrap(id, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't unwrap data key: %w", err)
+	}
+
+	s := secretbox.NewSecrets()
+	if err := s.Init(secrets.Key(key)); err != nil {
+		return nil, err
+	}
+	return s.Decrypt(b, opts...)
+}
+
+// KeyOf returns the id of the master key a value's data key was wrapped with
+func KeyOf(in []byte) (string, error) {
+	id, _, _, err := parse(in)
+	return id, err
+}
+
+func parse(in []byte) (string, []byte, []byte, error) {
+	invalid := errors.New("invalid kms ciphertext")
+
+	if len(in) < 2 || in[0] != version {
+		return "", nil, nil, invalid
+	}
+	n := int(in[1])
+	in = in[2:]
+	if len(in) < n+2 {
+		return "", nil, nil, invalid
+	}
+	id := string(in[:n])
+	in = in[n:]
+	w := int(binary.BigEndian.Uint16(in))
+	in = in[2:]
+	if len(in) < w {
+		return "", nil, nil, invalid
+	}
+	return id, in[:w], in[w:], nil
+}
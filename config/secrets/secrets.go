@@ -20,6 +20,10 @@ type Secrets interface {
 type Options struct {
 	// Key is a symmetric key for encoding
 	Key []byte
+	// KeyID identifies the key used for encoding
+	KeyID string
+	// Keys are additional keys for decoding by key id
+	Keys map[string][]byte
 	// Private key for decoding
 	PrivateKey []byte
 	// Public key for encoding
@@ -39,6 +43,24 @@ func Key(k []byte) Option {
 	}
 }
 
+// KeyID sets the id of the key used for encoding
+func KeyID(id string) Option {
+	return func(o *Options) {
+		o.KeyID = id
+	}
+}
+
+// DecryptKey adds a key which can decode values encoded with the key id
+func DecryptKey(id string, k []byte) Option {
+	return func(o *Options) {
+		if o.Keys == nil {
+			o.Keys = make(map[string][]byte)
+		}
+		o.Keys[id] = make([]byte, len(k))
+		copy(o.Keys[id], k)
+	}
+}
+
 // PublicKey sets the asymmetric Public Key of this codec
 func PublicKey(key []byte) Option {
 	return func(o *Options) {
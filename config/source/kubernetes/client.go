@@ -0,0 +1,207 @@
+package kubernetes
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ServiceAccountPath is where the pod's service account is mounted
+	ServiceAccountPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	// ErrNotFound is returned when the ConfigMap or Secret does not exist
+	ErrNotFound = errors.New("not found")
+)
+
+// object is a ConfigMap or Secret
+type object struct {
+	Metadata struct {
+		Name            string `json:"name"`
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Data       map[string]string `json:"data"`
+	BinaryData map[string][]byte `json:"binaryData"`
+}
+
+// event is a watch event
+type event struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// status is returned with an error
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// client is a minimal API server client for reading and
+// watching a single ConfigMap or Secret
+type client struct {
+	address   string
+	namespace string
+	name      string
+	secret    bool
+	token     string
+	http      *http.Client
+}
+
+func newClient(address, namespace, name, token string, secret bool, config *tls.Config) *client {
+	if len(address) == 0 {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if len(host) > 0 && len(port) > 0 {
+			address = "https://" + net.JoinHostPort(host, port)
+		}
+	}
+
+	if len(namespace) == 0 {
+		b, err := ioutil.ReadFile(filepath.Join(ServiceAccountPath, "namespace"))
+		if err == nil {
+			namespace = strings.TrimSpace(string(b))
+		} else {
+			namespace = "default"
+		}
+	}
+
+	if config == nil {
+		config = &tls.Config{}
+		if ca, err := ioutil.ReadFile(filepath.Join(ServiceAccountPath, "ca.crt")); err == nil {
+			pool := x509.NewCertPool()
+			pool.AppendCertsFromPEM(ca)
+			config.RootCAs = pool
+		}
+	}
+
+	return &client{
+		address:   strings.TrimSuffix(address, "/"),
+		namespace: namespace,
+		name:      name,
+		secret:    secret,
+		token:     token,
+		http: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: config,
+			},
+		},
+	}
+}
+
+func (c *client) resource() string {
+	if c.secret {
+		return "secrets"
+	}
+	return "configmaps"
+}
+
+func (c *client) do(path string, query url.Values) (*http.Response, error) {
+	if len(c.address) == 0 {
+		return nil, errors.New("kubernetes address not set and not running in a cluster")
+	}
+
+	u := fmt.Sprintf("%s/api/v1/namespaces/%s/%s%s", c.address, c.namespace, c.resource(), path)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the service account token is rotated so read it every time
+	token := c.token
+	if len(token) == 0 {
+		if b, err := ioutil.ReadFile(filepath.Join(ServiceAccountPath, "token")); err == nil {
+			token = strings.TrimSpace(string(b))
+		}
+	}
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	rsp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+		return rsp, nil
+	case http.StatusNotFound:
+		rsp.Body.Close()
+		return nil, ErrNotFound
+	}
+
+	defer rsp.Body.Close()
+
+	var s status
+	b, _ := ioutil.ReadAll(rsp.Body)
+	if err := json.Unmarshal(b, &s); err != nil || len(s.Message) == 0 {
+		return nil, fmt.Errorf("kubernetes error: %s", rsp.Status)
+	}
+	return nil, fmt.Errorf("kubernetes error: %s", s.Message)
+}
+
+// get the ConfigMap or Secret
+func (c *client) get() (*object, error) {
+	rsp, err := c.do("/"+c.name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	var obj *object
+	if err := json.NewDecoder(rsp.Body).Decode(&obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// watch the ConfigMap or Secret from a resource version
+func (c *client) watch(version string) (*http.Response, error) {
+	query := url.Values{
+		"watch":         {"true"},
+		"fieldSelector": {"metadata.name=" + c.name},
+	}
+	if len(version) > 0 {
+		query.Set("resourceVersion", version)
+	}
+	return c.do("", query)
+}
+
+// items returns the keys of the object
+func (c *client) items(obj *object) (map[string][]byte, error) {
+	items := make(map[string][]byte, len(obj.Data)+len(obj.BinaryData))
+
+	for k, v := range obj.BinaryData {
+		items[k] = v
+	}
+
+	for k, v := range obj.Data {
+		if !c.secret {
+			items[k] = []byte(v)
+			continue
+		}
+		// secret data is base64 encoded
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding secret key %s: %v", k, err)
+		}
+		items[k] = b
+	}
+
+	return items, nil
+}
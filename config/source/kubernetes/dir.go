@@ -0,0 +1,117 @@
+package kubernetes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"c-z.dev/go-micro/config/source"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// readDir reads the keys of a mounted ConfigMap or Secret. The kubelet
+// writes the keys to a timestamped directory and swaps the ..data symlink
+// so hidden entries are skipped.
+func readDir(dir string) (map[string][]byte, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string][]byte)
+
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+
+		// keys are symlinks into ..data
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if fi.IsDir() {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		items[e.Name()] = b
+	}
+
+	return items, nil
+}
+
+type dirWatcher struct {
+	k    *kubernetes
+	fw   *fsnotify.Watcher
+	exit chan bool
+	last string
+}
+
+func newDirWatcher(k *kubernetes) (source.Watcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fw.Add(k.dir); err != nil {
+		fw.Close()
+		return nil, err
+	}
+
+	w := &dirWatcher{
+		k:    k,
+		fw:   fw,
+		exit: make(chan bool),
+	}
+
+	if cs, err := k.Read(); err == nil {
+		w.last = cs.Checksum
+	}
+
+	return w, nil
+}
+
+func (w *dirWatcher) Next() (*source.ChangeSet, error) {
+	for {
+		select {
+		case <-w.exit:
+			return nil, source.ErrWatcherStopped
+		case err := <-w.fw.Errors:
+			return nil, err
+		case _, ok := <-w.fw.Events:
+			if !ok {
+				return nil, source.ErrWatcherStopped
+			}
+
+			// a swap creates several events, only return a change
+			cs, err := w.k.Read()
+			if err != nil {
+				// the swap may not be complete
+				continue
+			}
+			if cs.Checksum == w.last {
+				continue
+			}
+			w.last = cs.Checksum
+			return cs, nil
+		}
+	}
+}
+
+func (w *dirWatcher) Stop() error {
+	select {
+	case <-w.exit:
+		return nil
+	default:
+		close(w.exit)
+	}
+	return w.fw.Close()
+}
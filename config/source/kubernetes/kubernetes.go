@@ -0,0 +1,123 @@
+// Package kubernetes is a source for Kubernetes ConfigMaps and Secrets
+package kubernetes
+
+import (
+	"crypto/tls"
+	"time"
+
+	"c-z.dev/go-micro/config/encoder"
+	"c-z.dev/go-micro/config/encoder/json"
+	"c-z.dev/go-micro/config/encoder/xml"
+	"c-z.dev/go-micro/config/source"
+)
+
+// DefaultName is the name of the ConfigMap or Secret
+var DefaultName = "micro-config"
+
+type kubernetes struct {
+	opts     source.Options
+	dir      string
+	client   *client
+	decoders map[string]encoder.Encoder
+}
+
+func (k *kubernetes) Read() (*source.ChangeSet, error) {
+	if len(k.dir) > 0 {
+		items, err := readDir(k.dir)
+		if err != nil {
+			return nil, err
+		}
+		return k.fromItems(items)
+	}
+
+	obj, err := k.client.get()
+	if err != nil {
+		return nil, err
+	}
+	return k.fromObject(obj)
+}
+
+func (k *kubernetes) fromObject(obj *object) (*source.ChangeSet, error) {
+	items, err := k.client.items(obj)
+	if err != nil {
+		return nil, err
+	}
+	return k.fromItems(items)
+}
+
+func (k *kubernetes) fromItems(items map[string][]byte) (*source.ChangeSet, error) {
+	data, err := makeMap(k.decoders, items)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := k.opts.Encoder.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &source.ChangeSet{
+		Timestamp: time.Now(),
+		Source:    k.String(),
+		Data:      b,
+		Format:    k.opts.Encoder.String(),
+	}
+	cs.Checksum = cs.Sum()
+
+	return cs, nil
+}
+
+func (k *kubernetes) Watch() (source.Watcher, error) {
+	if len(k.dir) > 0 {
+		return newDirWatcher(k)
+	}
+	return newWatcher(k)
+}
+
+// Write is unsupported
+func (k *kubernetes) Write(cs *source.ChangeSet) error {
+	return nil
+}
+
+func (k *kubernetes) String() string {
+	return "kubernetes"
+}
+
+// NewSource returns a source which reads a ConfigMap, or a Secret with
+// WithSecret, from the API server or from the directory it's mounted at.
+// Keys with a json or xml extension, or the extension of a WithDecoder
+// encoder, are decoded and merged, other keys are set as string values.
+func NewSource(opts ...source.Option) source.Source {
+	options := source.NewOptions(opts...)
+
+	name := DefaultName
+	if n, ok := options.Context.Value(nameKey{}).(string); ok {
+		name = n
+	}
+	namespace, _ := options.Context.Value(namespaceKey{}).(string)
+	secret, _ := options.Context.Value(secretKey{}).(bool)
+	dir, _ := options.Context.Value(dirKey{}).(string)
+	address, _ := options.Context.Value(addressKey{}).(string)
+	token, _ := options.Context.Value(tokenKey{}).(string)
+	config, _ := options.Context.Value(tlsConfigKey{}).(*tls.Config)
+
+	decoders := make(map[string]encoder.Encoder)
+	for _, e := range []encoder.Encoder{json.NewEncoder(), xml.NewEncoder()} {
+		decoders[e.String()] = e
+	}
+	if d, ok := options.Context.Value(decoderKey{}).([]encoder.Encoder); ok {
+		for _, e := range d {
+			decoders[e.String()] = e
+		}
+	}
+	if e, ok := decoders["yaml"]; ok {
+		decoders["yml"] = e
+	}
+
+	return &kubernetes{
+		opts:     options,
+		dir:      dir,
+		client:   newClient(address, namespace, name, token, secret, config),
+		decoders: decoders,
+	}
+}
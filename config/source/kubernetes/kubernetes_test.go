@@ -0,0 +1,206 @@
+package kubernetes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/config/source"
+)
+
+// writeVolume lays out a mounted ConfigMap the way the kubelet does
+func writeVolume(t *testing.T, dir, version string, items map[string]string) {
+	data := filepath.Join(dir, version)
+	if err := os.Mkdir(data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range items {
+		if err := os.WriteFile(filepath.Join(data, k), []byte(v), 0644); err != nil {
+			t.Fatal(err)
+		}
+		link := filepath.Join(dir, k)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			if err := os.Symlink(filepath.Join("..data", k), link); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// atomically swap the data
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(version, tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func decode(t *testing.T, cs *source.ChangeSet) map[string]interface{} {
+	var data map[string]interface{}
+	if err := json.Unmarshal(cs.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	writeVolume(t, dir, "..2026_01", map[string]string{
+		"app.json":  `{"db": {"host": "a"}}`,
+		"LOG_LEVEL": "info",
+	})
+
+	src := NewSource(WithDir(dir))
+
+	cs, err := src.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := decode(t, cs)
+	if data["LOG_LEVEL"] != "info" || data["db"].(map[string]interface{})["host"] != "a" {
+		t.Fatalf("unexpected data %s", cs.Data)
+	}
+
+	w, err := src.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	writeVolume(t, dir, "..2026_02", map[string]string{
+		"app.json":  `{"db": {"host": "b"}}`,
+		"LOG_LEVEL": "info",
+	})
+
+	ch := make(chan *source.ChangeSet, 1)
+	go func() {
+		cs, err := w.Next()
+		if err != nil {
+			t.Error(err)
+		}
+		ch <- cs
+	}()
+
+	select {
+	case cs := <-ch:
+		if host := decode(t, cs)["db"].(map[string]interface{})["host"]; host != "b" {
+			t.Fatalf("expected host b got %v", host)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for change")
+	}
+}
+
+func TestAPI(t *testing.T) {
+	events := make(chan string, 1)
+
+	obj := func(version, level string) string {
+		return fmt.Sprintf(`{"metadata": {"name": "app", "resourceVersion": %q}, "data": {"LOG_LEVEL": %q}}`, version, level)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/namespaces/test/configmaps/app":
+			fmt.Fprint(w, obj("1", "info"))
+		case "/api/v1/namespaces/test/configmaps/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/v1/namespaces/test/configmaps":
+			if r.URL.Query().Get("watch") != "true" || r.URL.Query().Get("resourceVersion") != "1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			for {
+				select {
+				case ev := <-events:
+					fmt.Fprintln(w, ev)
+					w.(http.Flusher).Flush()
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}
+	}))
+	defer srv.Close()
+
+	src := NewSource(
+		WithAddress(srv.URL),
+		WithNamespace("test"),
+		WithName("app"),
+		WithToken("token"),
+	)
+
+	cs, err := src.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level := decode(t, cs)["LOG_LEVEL"]; level != "info" {
+		t.Fatalf("expected info got %v", level)
+	}
+
+	w, err := src.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// unchanged data is skipped
+	events <- fmt.Sprintf(`{"type": "MODIFIED", "object": %s}`, obj("2", "info"))
+	go func() {
+		events <- fmt.Sprintf(`{"type": "MODIFIED", "object": %s}`, obj("3", "debug"))
+	}()
+
+	cs, err = w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level := decode(t, cs)["LOG_LEVEL"]; level != "debug" {
+		t.Fatalf("expected debug got %v", level)
+	}
+
+	if _, err := NewSource(
+		WithAddress(srv.URL),
+		WithNamespace("test"),
+		WithName("missing"),
+		WithToken("token"),
+	).Read(); err != ErrNotFound {
+		t.Fatalf("expected not found got %v", err)
+	}
+}
+
+func TestSecret(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/test/secrets/app" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		v := base64.StdEncoding.EncodeToString([]byte(`{"password": "secret"}`))
+		fmt.Fprintf(w, `{"metadata": {"name": "app"}, "data": {"db.json": %q}}`, v)
+	}))
+	defer srv.Close()
+
+	cs, err := NewSource(
+		WithAddress(srv.URL),
+		WithNamespace("test"),
+		WithName("app"),
+		WithSecret(),
+	).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pass := decode(t, cs)["password"]; pass != "secret" {
+		t.Fatalf("expected secret got %v", pass)
+	}
+}
@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/tls"
+
+	"c-z.dev/go-micro/config/encoder"
+	"c-z.dev/go-micro/config/source"
+)
+
+type (
+	nameKey      struct{}
+	namespaceKey struct{}
+	secretKey    struct{}
+	dirKey       struct{}
+	addressKey   struct{}
+	tokenKey     struct{}
+	tlsConfigKey struct{}
+	decoderKey   struct{}
+)
+
+func setOption(k, v interface{}) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// WithName sets the name of the ConfigMap or Secret
+func WithName(n string) source.Option {
+	return setOption(nameKey{}, n)
+}
+
+// WithNamespace sets the namespace of the ConfigMap or Secret.
+// It defaults to the namespace of the pod.
+func WithNamespace(ns string) source.Option {
+	return setOption(namespaceKey{}, ns)
+}
+
+// WithSecret reads a Secret rather than a ConfigMap
+func WithSecret() source.Option {
+	return setOption(secretKey{}, true)
+}
+
+// WithDir reads the ConfigMap or Secret from the directory it's
+// mounted at rather than from the API server
+func WithDir(d string) source.Option {
+	return setOption(dirKey{}, d)
+}
+
+// WithAddress sets the API server address. It defaults
+// to the in-cluster address.
+func WithAddress(a string) source.Option {
+	return setOption(addressKey{}, a)
+}
+
+// WithToken sets the bearer token used with the API server.
+// It defaults to the pod's service account token.
+func WithToken(t string) source.Option {
+	return setOption(tokenKey{}, t)
+}
+
+// WithTLSConfig sets the TLS config used with the API server.
+// It defaults to trusting the service account CA.
+func WithTLSConfig(t *tls.Config) source.Option {
+	return setOption(tlsConfigKey{}, t)
+}
+
+// WithDecoder decodes keys with the encoder's extension e.g. a
+// yaml encoder decodes app.yaml. json and xml are decoded by default.
+func WithDecoder(e encoder.Encoder) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		decoders, _ := o.Context.Value(decoderKey{}).([]encoder.Encoder)
+		o.Context = context.WithValue(o.Context, decoderKey{}, append(decoders, e))
+	}
+}
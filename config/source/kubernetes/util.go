@@ -0,0 +1,45 @@
+package kubernetes
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"c-z.dev/go-micro/config/encoder"
+
+	"dario.cat/mergo"
+)
+
+// makeMap builds the config from the keys of a ConfigMap or Secret.
+// Keys with the extension of a decoder are decoded and merged at the
+// root, any other key is set as a string value.
+func makeMap(decoders map[string]encoder.Encoder, items map[string][]byte) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	// merge in a consistent order
+	keys := make([]string, 0, len(items))
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ext := strings.TrimPrefix(filepath.Ext(k), ".")
+		dec, ok := decoders[ext]
+		if !ok {
+			data[k] = string(items[k])
+			continue
+		}
+
+		var vals map[string]interface{}
+		if err := dec.Decode(items[k], &vals); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %v", k, err)
+		}
+		if err := mergo.Map(&data, vals, mergo.WithOverride); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
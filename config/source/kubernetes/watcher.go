@@ -0,0 +1,180 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"c-z.dev/go-micro/config/source"
+)
+
+type watcher struct {
+	k    *kubernetes
+	exit chan bool
+
+	// the last resource version and checksum seen
+	version string
+	last    string
+
+	sync.Mutex
+	body io.ReadCloser
+	dec  *json.Decoder
+}
+
+func newWatcher(k *kubernetes) (source.Watcher, error) {
+	obj, err := k.client.get()
+	if err != nil && err != ErrNotFound {
+		return nil, err
+	}
+
+	w := &watcher{
+		k:    k,
+		exit: make(chan bool),
+	}
+
+	if obj != nil {
+		w.version = obj.Metadata.ResourceVersion
+		if cs, err := k.fromObject(obj); err == nil {
+			w.last = cs.Checksum
+		}
+	}
+
+	return w, nil
+}
+
+// connect starts a watch from the last resource version
+func (w *watcher) connect() error {
+	rsp, err := w.k.client.watch(w.version)
+	if err != nil {
+		return err
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	select {
+	case <-w.exit:
+		rsp.Body.Close()
+		return source.ErrWatcherStopped
+	default:
+	}
+
+	w.body = rsp.Body
+	w.dec = json.NewDecoder(rsp.Body)
+	return nil
+}
+
+func (w *watcher) disconnect() {
+	w.Lock()
+	if w.body != nil {
+		w.body.Close()
+	}
+	w.body = nil
+	w.dec = nil
+	w.Unlock()
+}
+
+func (w *watcher) Next() (*source.ChangeSet, error) {
+	for {
+		select {
+		case <-w.exit:
+			return nil, source.ErrWatcherStopped
+		default:
+		}
+
+		w.Lock()
+		dec := w.dec
+		w.Unlock()
+
+		if dec == nil {
+			if err := w.connect(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var ev event
+		if err := dec.Decode(&ev); err != nil {
+			w.disconnect()
+			// the API server ends watches after a timeout
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				continue
+			}
+			select {
+			case <-w.exit:
+				return nil, source.ErrWatcherStopped
+			default:
+			}
+			return nil, err
+		}
+
+		var cs *source.ChangeSet
+		var err error
+
+		switch ev.Type {
+		case "ERROR":
+			var s status
+			json.Unmarshal(ev.Object, &s)
+			w.disconnect()
+
+			// the resource version is too old so read it again
+			if s.Code != http.StatusGone {
+				return nil, fmt.Errorf("kubernetes watch error: %s", s.Message)
+			}
+
+			obj, err := w.k.client.get()
+			if err == ErrNotFound {
+				w.version = ""
+				cs, err = w.k.fromItems(nil)
+			} else if err == nil {
+				w.version = obj.Metadata.ResourceVersion
+				cs, err = w.k.fromObject(obj)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case "BOOKMARK":
+			var obj *object
+			if err := json.Unmarshal(ev.Object, &obj); err == nil {
+				w.version = obj.Metadata.ResourceVersion
+			}
+			continue
+		default:
+			var obj *object
+			if err := json.Unmarshal(ev.Object, &obj); err != nil {
+				return nil, err
+			}
+			w.version = obj.Metadata.ResourceVersion
+
+			if ev.Type == "DELETED" {
+				cs, err = w.k.fromItems(nil)
+			} else {
+				cs, err = w.k.fromObject(obj)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// only return changes
+		if cs.Checksum == w.last {
+			continue
+		}
+		w.last = cs.Checksum
+
+		return cs, nil
+	}
+}
+
+func (w *watcher) Stop() error {
+	select {
+	case <-w.exit:
+		return nil
+	default:
+		close(w.exit)
+	}
+	w.disconnect()
+	return nil
+}
@@ -0,0 +1,67 @@
+package source
+
+// Layer is a source loaded with options which control
+// how it's combined with the other sources
+type Layer interface {
+	Source
+	// LayerOptions used to load the source
+	LayerOptions() LayerOptions
+}
+
+type LayerOptions struct {
+	// Mount the values under the path
+	Mount []string
+	// Prefix restricts the source to the values under the path.
+	// The prefix is removed before the values are mounted.
+	Prefix []string
+	// Priority orders the sources. Sources with a higher priority
+	// override those with a lower one, otherwise the later source wins.
+	Priority int
+}
+
+type LayerOption func(o *LayerOptions)
+
+type layer struct {
+	Source
+	opts LayerOptions
+}
+
+func (l *layer) LayerOptions() LayerOptions {
+	return l.opts
+}
+
+// NewLayer wraps the source with the layer options e.g.
+//
+//	conf.Load(
+//		file.NewSource(file.WithPath("defaults.json")),
+//		source.NewLayer(env.NewSource(), source.Prefix("myapp"), source.Priority(10)),
+//		source.NewLayer(etcd.NewSource(), source.Mount("remote")),
+//	)
+func NewLayer(s Source, opts ...LayerOption) Source {
+	var options LayerOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	return &layer{Source: s, opts: options}
+}
+
+// Mount the values of the source under the path
+func Mount(path ...string) LayerOption {
+	return func(o *LayerOptions) {
+		o.Mount = path
+	}
+}
+
+// Prefix restricts the source to the values under the path
+func Prefix(path ...string) LayerOption {
+	return func(o *LayerOptions) {
+		o.Prefix = path
+	}
+}
+
+// Priority sets the priority of the source
+func Priority(p int) LayerOption {
+	return func(o *LayerOptions) {
+		o.Priority = p
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	ejson "encoding/json"
+	"strings"
+
+	"c-z.dev/go-micro/config/loader"
+	"c-z.dev/go-micro/config/reader/json"
+	"c-z.dev/go-micro/config/source"
+	"c-z.dev/go-micro/config/source/service/proto"
+	"c-z.dev/go-micro/errors"
+)
+
+// HistoryHandler serves the Config.History endpoint, config services embed
+// it in their proto.ConfigHandler to serve the history of their loaders
+type HistoryHandler struct {
+	// Loader returns the loader of the namespace, a nil loader is not found
+	Loader func(namespace string) (loader.History, error)
+}
+
+// History returns the snapshots of the namespace, oldest first. If a dot
+// separated path is requested the snapshots hold the values at the path.
+func (h *HistoryHandler) History(ctx context.Context, req *proto.HistoryRequest, rsp *proto.HistoryResponse) error {
+	if len(req.Namespace) == 0 {
+		return errors.BadRequest(DefaultName+".History", "namespace is required")
+	}
+
+	l, err := h.Loader(req.Namespace)
+	if err != nil {
+		return errors.InternalServerError(DefaultName+".History", "%v", err)
+	}
+	if l == nil {
+		return errors.NotFound(DefaultName+".History", "namespace %s not found", req.Namespace)
+	}
+
+	snaps := l.History()
+	if len(req.Path) > 0 {
+		snaps, err = pathSnapshots(snaps, strings.Split(req.Path, "."))
+		if err != nil {
+			return errors.InternalServerError(DefaultName+".History", "%v", err)
+		}
+	}
+
+	rsp.Snapshots = ToSnapshots(snaps)
+	return nil
+}
+
+// pathSnapshots returns the snapshots holding only the values at the path
+func pathSnapshots(snaps []*loader.Snapshot, path []string) ([]*loader.Snapshot, error) {
+	rd := json.NewReader()
+
+	out := make([]*loader.Snapshot, 0, len(snaps))
+	for _, snap := range snaps {
+		vals, err := rd.Values(snap.ChangeSet)
+		if err != nil {
+			return nil, err
+		}
+		var v interface{}
+		if err := vals.Get(path...).Scan(&v); err != nil {
+			return nil, err
+		}
+		b, err := ejson.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		cs := &source.ChangeSet{
+			Data:      b,
+			Format:    rd.String(),
+			Source:    snap.ChangeSet.Source,
+			Timestamp: snap.ChangeSet.Timestamp,
+		}
+		cs.Checksum = cs.Sum()
+
+		out = append(out, &loader.Snapshot{ChangeSet: cs, Version: snap.Version})
+	}
+	return out, nil
+}
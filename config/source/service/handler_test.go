@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"c-z.dev/go-micro/config/loader"
+	"c-z.dev/go-micro/config/loader/memory"
+	msource "c-z.dev/go-micro/config/source/memory"
+	"c-z.dev/go-micro/config/source/service/proto"
+	"c-z.dev/go-micro/errors"
+)
+
+func TestHistoryHandler(t *testing.T) {
+	l := memory.NewLoader(memory.WithHistory(2))
+	defer l.Close()
+
+	if err := l.Load(msource.NewSource(msource.WithJSON([]byte(`{"db": {"host": "a"}}`)))); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &HistoryHandler{Loader: func(ns string) (loader.History, error) {
+		if ns != DefaultNamespace {
+			return nil, nil
+		}
+		return l.(loader.History), nil
+	}}
+
+	rsp := &proto.HistoryResponse{}
+	if err := h.History(context.TODO(), &proto.HistoryRequest{Namespace: DefaultNamespace}, rsp); err != nil {
+		t.Fatal(err)
+	}
+	if len(rsp.Snapshots) != 1 {
+		t.Fatalf("expected 1 snapshot got %d", len(rsp.Snapshots))
+	}
+
+	snap := toSnapshot(rsp.Snapshots[0])
+	if snap.Version != l.(loader.History).History()[0].Version || string(snap.ChangeSet.Data) != `{"db":{"host":"a"}}` {
+		t.Fatalf("unexpected snapshot %s %s", snap.Version, snap.ChangeSet.Data)
+	}
+
+	// the snapshots hold the values at the path requested
+	rsp = &proto.HistoryResponse{}
+	if err := h.History(context.TODO(), &proto.HistoryRequest{Namespace: DefaultNamespace, Path: "db.host"}, rsp); err != nil {
+		t.Fatal(err)
+	}
+	if len(rsp.Snapshots) != 1 || rsp.Snapshots[0].ChangeSet.Data != `"a"` {
+		t.Fatalf("unexpected snapshots %v", rsp.Snapshots)
+	}
+
+	err := h.History(context.TODO(), &proto.HistoryRequest{Namespace: "foo"}, &proto.HistoryResponse{})
+	if verr, ok := err.(*errors.Error); !ok || verr.Code != 404 {
+		t.Fatalf("expected not found error got %v", err)
+	}
+}
@@ -32,6 +32,7 @@ type ConfigService interface {
 	List(ctx context.Context, in *ListRequest, opts ...client.CallOption) (*ListResponse, error)
 	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
 	Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Config_WatchService, error)
+	History(ctx context.Context, in *HistoryRequest, opts ...client.CallOption) (*HistoryResponse, error)
 }
 
 type configService struct {
@@ -145,6 +146,16 @@ func (x *configServiceWatch) Recv() (*WatchResponse, error) {
 	return m, nil
 }
 
+func (c *configService) History(ctx context.Context, in *HistoryRequest, opts ...client.CallOption) (*HistoryResponse, error) {
+	req := c.c.NewRequest(c.name, "Config.History", in)
+	out := new(HistoryResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConfigHandler is the server API for Config service.
 type ConfigHandler interface {
 	Create(context.Context, *CreateRequest, *CreateResponse) error
@@ -153,6 +164,7 @@ type ConfigHandler interface {
 	List(context.Context, *ListRequest, *ListResponse) error
 	Read(context.Context, *ReadRequest, *ReadResponse) error
 	Watch(context.Context, *WatchRequest, Config_WatchStream) error
+	History(context.Context, *HistoryRequest, *HistoryResponse) error
 }
 
 func RegisterConfigHandler(s server.Server, hdlr ConfigHandler, opts ...server.HandlerOption) error {
@@ -163,6 +175,7 @@ func RegisterConfigHandler(s server.Server, hdlr ConfigHandler, opts ...server.H
 		List(ctx context.Context, in *ListRequest, out *ListResponse) error
 		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
 		Watch(ctx context.Context, stream server.Stream) error
+		History(ctx context.Context, in *HistoryRequest, out *HistoryResponse) error
 	}
 	type Config struct {
 		config
@@ -234,3 +247,7 @@ func (x *configWatchStream) RecvMsg(m interface{}) error {
 func (x *configWatchStream) Send(m *WatchResponse) error {
 	return x.stream.Send(m)
 }
+
+func (h *configHandler) History(ctx context.Context, in *HistoryRequest, out *HistoryResponse) error {
+	return h.ConfigHandler.History(ctx, in, out)
+}
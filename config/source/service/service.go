@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"c-z.dev/go-micro/client"
+	"c-z.dev/go-micro/config/loader"
 	"c-z.dev/go-micro/config/source"
 	"c-z.dev/go-micro/config/source/service/proto"
 	"c-z.dev/go-micro/errors"
@@ -55,6 +56,25 @@ func (m *service) Watch() (w source.Watcher, err error) {
 	return newWatcher(stream)
 }
 
+// History returns the snapshots retained by the config service, oldest first
+func (m *service) History() ([]*loader.Snapshot, error) {
+	client := proto.NewConfigService(m.serviceName, m.opts.Client)
+	rsp, err := client.History(context.Background(), &proto.HistoryRequest{
+		Namespace: m.namespace,
+		Path:      m.path,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snaps := make([]*loader.Snapshot, 0, len(rsp.Snapshots))
+	for _, snap := range rsp.Snapshots {
+		snaps = append(snaps, toSnapshot(snap))
+	}
+
+	return snaps, nil
+}
+
 // Write is unsupported
 func (m *service) Write(cs *source.ChangeSet) error {
 	return nil
@@ -104,3 +124,8 @@ func NewSource(opts ...source.Option) source.Source {
 
 	return s
 }
+
+// History reads the snapshot history of the config service
+func History(opts ...source.Option) ([]*loader.Snapshot, error) {
+	return NewSource(opts...).(*service).History()
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"time"
 
+	"c-z.dev/go-micro/config/loader"
 	"c-z.dev/go-micro/config/source"
 	"c-z.dev/go-micro/config/source/service/proto"
 )
@@ -16,3 +17,32 @@ func toChangeSet(c *proto.ChangeSet) *source.ChangeSet {
 		Source:    c.Source,
 	}
 }
+
+func toSnapshot(s *proto.Snapshot) *loader.Snapshot {
+	snap := &loader.Snapshot{
+		Version:   s.Version,
+		ChangeSet: &source.ChangeSet{},
+	}
+	if s.ChangeSet != nil {
+		snap.ChangeSet = toChangeSet(s.ChangeSet)
+	}
+	return snap
+}
+
+// ToSnapshots converts the loader history for a History response
+func ToSnapshots(snaps []*loader.Snapshot) []*proto.Snapshot {
+	out := make([]*proto.Snapshot, 0, len(snaps))
+	for _, snap := range snaps {
+		out = append(out, &proto.Snapshot{
+			Version: snap.Version,
+			ChangeSet: &proto.ChangeSet{
+				Data:      string(snap.ChangeSet.Data),
+				Checksum:  snap.ChangeSet.Checksum,
+				Format:    snap.ChangeSet.Format,
+				Source:    snap.ChangeSet.Source,
+				Timestamp: snap.ChangeSet.Timestamp.Unix(),
+			},
+		})
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package url
+
+import (
+	"mime"
+	"path"
+	"strings"
+)
+
+// formats maps content types to encoder names
+var formats = map[string]string{
+	"application/json":   "json",
+	"text/json":          "json",
+	"application/xml":    "xml",
+	"text/xml":           "xml",
+	"application/yaml":   "yaml",
+	"application/x-yaml": "yaml",
+	"text/yaml":          "yaml",
+	"text/x-yaml":        "yaml",
+	"application/toml":   "toml",
+	"text/toml":          "toml",
+	"application/hcl":    "hcl",
+}
+
+// format picks the format from the content type, then the
+// extension of the url path, then the default encoder
+func format(contentType, urlPath, def string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		if f, ok := formats[mt]; ok {
+			return f
+		}
+		// e.g. application/vnd.config+json
+		if i := strings.LastIndex(mt, "+"); i > 0 {
+			return mt[i+1:]
+		}
+	}
+
+	if ext := strings.TrimPrefix(path.Ext(urlPath), "."); len(ext) > 0 {
+		if ext == "yml" {
+			return "yaml"
+		}
+		return ext
+	}
+
+	return def
+}
@@ -0,0 +1,72 @@
+package url
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"c-z.dev/go-micro/config/source"
+)
+
+type (
+	urlKey       struct{}
+	headerKey    struct{}
+	intervalKey  struct{}
+	longPollKey  struct{}
+	timeoutKey   struct{}
+	tlsConfigKey struct{}
+)
+
+func setOption(k, v interface{}) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// WithURL sets the url of the config document
+func WithURL(u string) source.Option {
+	return setOption(urlKey{}, u)
+}
+
+// WithHeader sets a header sent with each request e.g. Authorization
+func WithHeader(k, v string) source.Option {
+	return func(o *source.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		h, ok := o.Context.Value(headerKey{}).(http.Header)
+		if ok {
+			h = h.Clone()
+		} else {
+			h = make(http.Header)
+		}
+		h.Add(k, v)
+		o.Context = context.WithValue(o.Context, headerKey{}, h)
+	}
+}
+
+// WithInterval sets how often the url is polled for changes
+func WithInterval(d time.Duration) source.Option {
+	return setOption(intervalKey{}, d)
+}
+
+// WithLongPoll asks the server to hold each poll for up to the wait
+// until the document changes, using the Prefer: wait header
+func WithLongPoll(wait time.Duration) source.Option {
+	return setOption(longPollKey{}, wait)
+}
+
+// WithTimeout sets how long a request may take, long polls
+// may take this long on top of their wait
+func WithTimeout(d time.Duration) source.Option {
+	return setOption(timeoutKey{}, d)
+}
+
+// WithTLSConfig sets the TLS config, see util/tls.Config
+func WithTLSConfig(t *tls.Config) source.Option {
+	return setOption(tlsConfigKey{}, t)
+}
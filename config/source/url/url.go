@@ -0,0 +1,166 @@
+// Package url is a source which reads config from a url
+package url
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"time"
+
+	"c-z.dev/go-micro/config/source"
+)
+
+var (
+	// DefaultURL is the url of the config document
+	DefaultURL = "http://localhost:8080/config"
+	// DefaultInterval is how often the url is polled
+	DefaultInterval = time.Second * 30
+	// DefaultTimeout is how long a request may take, long polls may
+	// take this long on top of their wait
+	DefaultTimeout = time.Second * 10
+)
+
+type urlSource struct {
+	url      string
+	header   http.Header
+	interval time.Duration
+	longPoll time.Duration
+	timeout  time.Duration
+	client   *http.Client
+	opts     source.Options
+}
+
+// response is a fetched document
+type response struct {
+	cs       *source.ChangeSet
+	etag     string
+	modified bool
+}
+
+// fetch the document. The etag is sent with If-None-Match so
+// the server can reply with not modified. The request is abandoned
+// if the server hasn't answered within the wait and timeout.
+func (u *urlSource) fetch(ctx context.Context, etag string, wait time.Duration) (*response, error) {
+	req, err := http.NewRequest(http.MethodGet, u.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, wait+u.timeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	for k, v := range u.header {
+		req.Header[k] = v
+	}
+	if len(etag) > 0 {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if wait > 0 {
+		req.Header.Set("Prefer", fmt.Sprintf("wait=%d", int(wait.Seconds())))
+	}
+
+	rsp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return &response{etag: etag}, nil
+	default:
+		return nil, fmt.Errorf("error reading %s: %s", u.url, rsp.Status)
+	}
+
+	b, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now()
+	if t, err := http.ParseTime(rsp.Header.Get("Last-Modified")); err == nil {
+		timestamp = t
+	}
+
+	var urlPath string
+	if p, err := neturl.Parse(u.url); err == nil {
+		urlPath = p.Path
+	}
+
+	cs := &source.ChangeSet{
+		Data:      b,
+		Format:    format(rsp.Header.Get("Content-Type"), urlPath, u.opts.Encoder.String()),
+		Source:    u.String(),
+		Timestamp: timestamp,
+	}
+	cs.Checksum = cs.Sum()
+
+	return &response{
+		cs:       cs,
+		etag:     rsp.Header.Get("ETag"),
+		modified: true,
+	}, nil
+}
+
+func (u *urlSource) Read() (*source.ChangeSet, error) {
+	rsp, err := u.fetch(context.Background(), "", 0)
+	if err != nil {
+		return nil, err
+	}
+	return rsp.cs, nil
+}
+
+func (u *urlSource) Watch() (source.Watcher, error) {
+	return newWatcher(u)
+}
+
+// Write is unsupported
+func (u *urlSource) Write(cs *source.ChangeSet) error {
+	return nil
+}
+
+func (u *urlSource) String() string {
+	return "url"
+}
+
+// NewSource returns a source which reads a config document from a url.
+// The format is picked from the content type or the url extension.
+func NewSource(opts ...source.Option) source.Source {
+	options := source.NewOptions(opts...)
+
+	u := &urlSource{
+		url:      DefaultURL,
+		header:   make(http.Header),
+		interval: DefaultInterval,
+		timeout:  DefaultTimeout,
+		opts:     options,
+	}
+
+	if v, ok := options.Context.Value(urlKey{}).(string); ok {
+		u.url = v
+	}
+	if v, ok := options.Context.Value(headerKey{}).(http.Header); ok {
+		u.header = v
+	}
+	if v, ok := options.Context.Value(intervalKey{}).(time.Duration); ok {
+		u.interval = v
+	}
+	if v, ok := options.Context.Value(longPollKey{}).(time.Duration); ok {
+		u.longPoll = v
+	}
+	if v, ok := options.Context.Value(timeoutKey{}).(time.Duration); ok {
+		u.timeout = v
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if v, ok := options.Context.Value(tlsConfigKey{}).(*tls.Config); ok {
+		transport.TLSClientConfig = v
+	}
+	u.client = &http.Client{Transport: transport}
+
+	return u
+}
@@ -0,0 +1,184 @@
+package url
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/config/source"
+)
+
+// document is a config document served with an etag
+type document struct {
+	sync.Mutex
+	version int
+	data    string
+	changed chan bool
+	// ignoreWait answers long polls at once
+	ignoreWait bool
+	requests   int32
+}
+
+func (d *document) set(data string) {
+	d.Lock()
+	d.version++
+	d.data = data
+	d.Unlock()
+
+	select {
+	case d.changed <- true:
+	default:
+	}
+}
+
+func (d *document) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&d.requests, 1)
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	d.Lock()
+	etag := fmt.Sprintf(`"%d"`, d.version)
+	d.Unlock()
+
+	if r.Header.Get("If-None-Match") == etag {
+		// hold long polls until the document changes
+		if len(r.Header.Get("Prefer")) == 0 || d.ignoreWait {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		select {
+		case <-d.changed:
+		case <-time.After(time.Second):
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, d.version))
+	fmt.Fprint(w, d.data)
+}
+
+func TestURL(t *testing.T) {
+	testData := []struct {
+		name string
+		tls  bool
+	}{
+		{name: "poll"},
+		{name: "long poll"},
+		{name: "long poll ignored"},
+		{name: "tls", tls: true},
+	}
+
+	for _, d := range testData {
+		t.Run(d.name, func(t *testing.T) {
+			doc := &document{changed: make(chan bool, 1), ignoreWait: d.name == "long poll ignored"}
+			doc.set("level: info")
+
+			var srv *httptest.Server
+			if d.tls {
+				srv = httptest.NewTLSServer(doc)
+			} else {
+				srv = httptest.NewServer(doc)
+			}
+			defer srv.Close()
+
+			options := []source.Option{
+				WithURL(srv.URL + "/config"),
+				WithHeader("Authorization", "Bearer token"),
+				WithInterval(10 * time.Millisecond),
+			}
+			if d.name != "poll" && !d.tls {
+				options = append(options, WithLongPoll(time.Second))
+			}
+			if d.tls {
+				pool := x509.NewCertPool()
+				pool.AddCert(srv.Certificate())
+				options = append(options, WithTLSConfig(&tls.Config{RootCAs: pool}))
+			}
+
+			s := NewSource(options...)
+
+			cs, err := s.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cs.Format != "yaml" || string(cs.Data) != "level: info" {
+				t.Fatalf("unexpected change set %s %s", cs.Format, cs.Data)
+			}
+
+			w, err := s.Watch()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Stop()
+
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				doc.set("level: debug")
+			}()
+
+			cs, err = w.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(cs.Data) != "level: debug" {
+				t.Fatalf("expected level debug got %s", cs.Data)
+			}
+
+			// polls are paced by the interval
+			if n := atomic.LoadInt32(&doc.requests); n > 20 {
+				t.Fatalf("expected polls to be delayed got %d requests", n)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	done := make(chan bool)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	s := NewSource(WithURL(srv.URL), WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	if _, err := s.Read(); err == nil {
+		t.Fatal("expected the read to time out")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected the read to time out after 50ms took %v", d)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	testData := []struct {
+		contentType string
+		path        string
+		format      string
+	}{
+		{"application/json", "/config", "json"},
+		{"application/vnd.config+toml", "/config", "toml"},
+		{"text/plain", "/config.yml", "yaml"},
+		{"", "/config", "json"},
+	}
+
+	for _, d := range testData {
+		if f := format(d.contentType, d.path, "json"); f != d.format {
+			t.Fatalf("expected %s for %s %s got %s", d.format, d.contentType, d.path, f)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package url
+
+import (
+	"context"
+	"time"
+
+	"c-z.dev/go-micro/config/source"
+	"c-z.dev/go-micro/logger"
+)
+
+type watcher struct {
+	u      *urlSource
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// the etag and checksum of the last document
+	etag string
+	last string
+	// whether the next poll should wait
+	wait bool
+}
+
+func newWatcher(u *urlSource) (source.Watcher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := &watcher{
+		u:      u,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	rsp, err := u.fetch(ctx, "", 0)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	w.etag = rsp.etag
+	w.last = rsp.cs.Checksum
+	w.wait = u.longPoll == 0
+
+	return w, nil
+}
+
+func (w *watcher) Next() (*source.ChangeSet, error) {
+	for {
+		if w.wait {
+			select {
+			case <-w.ctx.Done():
+				return nil, source.ErrWatcherStopped
+			case <-time.After(w.u.interval):
+			}
+		}
+
+		start := time.Now()
+		rsp, err := w.u.fetch(w.ctx, w.etag, w.u.longPoll)
+
+		select {
+		case <-w.ctx.Done():
+			return nil, source.ErrWatcherStopped
+		default:
+		}
+
+		// long polls are delayed after an error or an early answer,
+		// e.g. from a server which ignores the Prefer: wait header
+		w.wait = w.u.longPoll == 0 || err != nil || time.Since(start) < w.u.longPoll
+
+		if err != nil {
+			// keep polling so no change is missed
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("[url] %v", err)
+			}
+			continue
+		}
+
+		if !rsp.modified {
+			continue
+		}
+
+		w.etag = rsp.etag
+		if rsp.cs.Checksum == w.last {
+			continue
+		}
+		w.last = rsp.cs.Checksum
+
+		// a change ends a long poll early so the next one isn't delayed
+		if w.u.longPoll > 0 {
+			w.wait = false
+		}
+
+		return rsp.cs, nil
+	}
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
@@ -0,0 +1,22 @@
+module c-z.dev/go-micro/extension/broker/mqtt
+
+go 1.21
+
+require (
+	c-z.dev/go-micro v0.0.0-20220331184351-30c877cc3979
+	github.com/eclipse/paho.mqtt.golang v1.4.3
+	github.com/google/uuid v1.5.0
+	github.com/mochi-mqtt/server/v2 v2.4.6
+)
+
+require (
+	github.com/gorilla/websocket v1.5.0 // indirect
+	github.com/miekg/dns v1.1.57 // indirect
+	github.com/rs/xid v1.4.0 // indirect
+	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sync v0.4.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/protobuf v1.32.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
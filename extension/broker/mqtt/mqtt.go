@@ -0,0 +1,375 @@
+// Package mqtt provides an MQTT broker
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/codec/json"
+	"c-z.dev/go-micro/logger"
+	"c-z.dev/go-micro/registry"
+)
+
+type mqttBroker struct {
+	sync.RWMutex
+
+	addrs  []string
+	client mqtt.Client
+	opts   broker.Options
+	copts  *mqtt.ClientOptions
+	qos    byte
+	// id of the client, kept across connections so the session is resumed
+	id string
+	// subscriptions made again when the client reconnects
+	subs map[string]mqtt.MessageHandler
+}
+
+type subscriber struct {
+	topic  string
+	filter string
+	opts   broker.SubscribeOptions
+	broker *mqttBroker
+}
+
+type publication struct {
+	t   string
+	err error
+	m   *broker.Message
+	msg mqtt.Message
+}
+
+var (
+	// DefaultAddress is used when no address is specified
+	DefaultAddress = "tcp://127.0.0.1:1883"
+	// DefaultTimeout bounds connect, publish and subscribe calls
+	DefaultTimeout = time.Second * 10
+)
+
+func (p *publication) Topic() string {
+	return p.t
+}
+
+func (p *publication) Message() *broker.Message {
+	return p.m
+}
+
+func (p *publication) Ack() error {
+	p.msg.Ack()
+	return nil
+}
+
+func (p *publication) Error() error {
+	return p.err
+}
+
+func (s *subscriber) Options() broker.SubscribeOptions {
+	return s.opts
+}
+
+func (s *subscriber) Topic() string {
+	return s.topic
+}
+
+func (s *subscriber) Unsubscribe() error {
+	m := s.broker
+
+	m.Lock()
+	delete(m.subs, s.filter)
+	c := m.client
+	m.Unlock()
+
+	if c == nil {
+		return errors.New("not connected")
+	}
+	return wait(c.Unsubscribe(s.filter))
+}
+
+// wait blocks until the token completes and returns its error
+func wait(t mqtt.Token) error {
+	if !t.WaitTimeout(DefaultTimeout) {
+		return errors.New("mqtt: timed out")
+	}
+	return t.Error()
+}
+
+// mqttTopic converts a micro topic to an mqtt topic. The dot separator
+// and the * and > wildcards map to /, + and # respectively.
+func mqttTopic(topic string) string {
+	parts := strings.Split(topic, ".")
+	for i, part := range parts {
+		switch part {
+		case "*":
+			parts[i] = "+"
+		case ">":
+			parts[i] = "#"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// microTopic converts an mqtt topic back to a micro topic
+func microTopic(topic string) string {
+	return strings.Replace(topic, "/", ".", -1)
+}
+
+func (m *mqttBroker) Address() string {
+	if len(m.addrs) > 0 {
+		return m.addrs[0]
+	}
+	return ""
+}
+
+func (m *mqttBroker) setAddrs(addrs []string) []string {
+	//nolint:prealloc
+	var cAddrs []string
+	for _, addr := range addrs {
+		if len(addr) == 0 {
+			continue
+		}
+		if !strings.Contains(addr, "://") {
+			scheme := "tcp://"
+			if m.opts.Secure || m.opts.TLSConfig != nil {
+				scheme = "ssl://"
+			}
+			addr = scheme + addr
+		}
+		cAddrs = append(cAddrs, addr)
+	}
+	if len(cAddrs) == 0 {
+		cAddrs = []string{DefaultAddress}
+	}
+	return cAddrs
+}
+
+func (m *mqttBroker) Connect() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.client != nil && m.client.IsConnected() {
+		return nil
+	}
+
+	opts := *m.copts
+	opts.Servers = nil
+	for _, addr := range m.addrs {
+		opts.AddBroker(addr)
+	}
+	if len(opts.ClientID) == 0 {
+		opts.SetClientID(m.id)
+	}
+	if m.opts.TLSConfig != nil {
+		opts.SetTLSConfig(m.opts.TLSConfig)
+	}
+	// messages are acked by the subscriber
+	opts.SetAutoAckDisabled(true)
+	// the session outlives the connection so messages which weren't
+	// acked are redelivered and subscriptions kept on reconnect
+	opts.SetCleanSession(false)
+	opts.SetResumeSubs(true)
+	// subscriptions are made again in case the session was lost
+	onConnect := opts.OnConnect
+	opts.SetOnConnectHandler(func(c mqtt.Client) {
+		m.resubscribe(c)
+		if onConnect != nil {
+			onConnect(c)
+		}
+	})
+
+	c := mqtt.NewClient(&opts)
+	if err := wait(c.Connect()); err != nil {
+		return err
+	}
+
+	m.client = c
+	return nil
+}
+
+// resubscribe makes the subscriptions again on a reconnected client
+func (m *mqttBroker) resubscribe(c mqtt.Client) {
+	m.RLock()
+	subs := make(map[string]mqtt.MessageHandler, len(m.subs))
+	for filter, fn := range m.subs {
+		subs[filter] = fn
+	}
+	qos := m.qos
+	m.RUnlock()
+
+	for filter, fn := range subs {
+		if err := wait(c.Subscribe(filter, qos, fn)); err != nil {
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Errorf("Error resubscribing to %s: %v", filter, err)
+			}
+		}
+	}
+}
+
+func (m *mqttBroker) Disconnect() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.client == nil {
+		return nil
+	}
+
+	m.client.Disconnect(250)
+	m.client = nil
+
+	return nil
+}
+
+func (m *mqttBroker) Init(opts ...broker.Option) error {
+	m.Lock()
+	defer m.Unlock()
+	m.setOption(opts...)
+	return nil
+}
+
+func (m *mqttBroker) Options() broker.Options {
+	return m.opts
+}
+
+func (m *mqttBroker) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	m.RLock()
+	c := m.client
+	codec := m.opts.Codec
+	qos := m.qos
+	m.RUnlock()
+
+	if c == nil {
+		return errors.New("not connected")
+	}
+
+	b, err := codec.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	// the lock isn't held while waiting so a slow publish doesn't block the broker
+	return wait(c.Publish(mqttTopic(topic), qos, false, b))
+}
+
+func (m *mqttBroker) Subscribe(topic string, handler broker.Handler, opts ...broker.SubscribeOption) (broker.Subscriber, error) {
+	m.RLock()
+	c := m.client
+	m.RUnlock()
+
+	if c == nil {
+		return nil, errors.New("not connected")
+	}
+
+	opt := broker.SubscribeOptions{
+		AutoAck: true,
+		Context: context.Background(),
+	}
+
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	fn := func(_ mqtt.Client, msg mqtt.Message) {
+		var bm broker.Message
+		pub := &publication{t: microTopic(msg.Topic()), msg: msg, m: &bm}
+		eh := m.opts.ErrorHandler
+
+		if err := m.opts.Codec.Unmarshal(msg.Payload(), &bm); err != nil {
+			pub.err = err
+			bm.Body = msg.Payload()
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Error(err)
+			}
+			if eh != nil {
+				eh(pub)
+			}
+			// the message can never be decoded so don't redeliver it
+			msg.Ack()
+			return
+		}
+
+		if err := handler(pub); err != nil {
+			pub.err = err
+			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+				logger.Error(err)
+			}
+			if eh != nil {
+				eh(pub)
+			}
+			return
+		}
+
+		if opt.AutoAck {
+			msg.Ack()
+		}
+	}
+
+	filter := mqttTopic(topic)
+	// queue subscribers share messages using mqtt shared subscriptions
+	if len(opt.Queue) > 0 {
+		filter = fmt.Sprintf("$share/%s/%s", opt.Queue, filter)
+	}
+
+	if err := wait(c.Subscribe(filter, m.qos, fn)); err != nil {
+		return nil, err
+	}
+
+	m.Lock()
+	m.subs[filter] = fn
+	m.Unlock()
+
+	return &subscriber{
+		topic:  topic,
+		filter: filter,
+		opts:   opt,
+		broker: m,
+	}, nil
+}
+
+func (m *mqttBroker) String() string {
+	return "mqtt"
+}
+
+func (m *mqttBroker) setOption(opts ...broker.Option) {
+	for _, o := range opts {
+		o(&m.opts)
+	}
+
+	if m.copts == nil {
+		m.copts = mqtt.NewClientOptions()
+	}
+
+	if copts, ok := m.opts.Context.Value(clientOptionsKey{}).(*mqtt.ClientOptions); ok {
+		m.copts = copts
+	}
+
+	if qos, ok := m.opts.Context.Value(qosKey{}).(byte); ok {
+		m.qos = qos
+	}
+
+	m.addrs = m.setAddrs(m.opts.Addrs)
+}
+
+func NewBroker(opts ...broker.Option) broker.Broker {
+	options := broker.Options{
+		// Default codec
+		Codec:    json.Marshaler{},
+		Context:  context.Background(),
+		Registry: registry.DefaultRegistry,
+	}
+
+	m := &mqttBroker{
+		opts: options,
+		qos:  1,
+		id:   "micro-" + uuid.New().String(),
+		subs: make(map[string]mqtt.MessageHandler),
+	}
+	m.setOption(opts...)
+
+	return m
+}
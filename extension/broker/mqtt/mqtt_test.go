@@ -0,0 +1,168 @@
+package mqtt
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"c-z.dev/go-micro/broker"
+
+	server "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+)
+
+func newServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	s := server.New(nil)
+	if err := s.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddListener(listeners.NewTCP("tcp", addr, nil)); err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve()
+	t.Cleanup(func() { s.Close() })
+
+	return addr
+}
+
+func newBroker(t *testing.T, addr string) broker.Broker {
+	b := NewBroker(broker.Addrs(addr))
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+	t.Cleanup(func() { b.Disconnect() })
+	return b
+}
+
+func TestTopicMapping(t *testing.T) {
+	testData := []struct {
+		micro string
+		mqtt  string
+	}{
+		{"go.micro.topic", "go/micro/topic"},
+		{"orders.*", "orders/+"},
+		{"orders.>", "orders/#"},
+		{"orders.*.created", "orders/+/created"},
+		{"orders.*x", "orders/*x"},
+	}
+
+	for _, d := range testData {
+		if got := mqttTopic(d.micro); got != d.mqtt {
+			t.Fatalf("Expected %s for %s got %s", d.mqtt, d.micro, got)
+		}
+	}
+
+	if got := microTopic("go/micro/topic"); got != "go.micro.topic" {
+		t.Fatalf("Expected go.micro.topic got %s", got)
+	}
+}
+
+func TestPubSub(t *testing.T) {
+	b := newBroker(t, newServer(t))
+
+	received := make(chan broker.Event, 1)
+
+	sub, err := b.Subscribe("orders.*", func(e broker.Event) error {
+		received <- e
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	msg := &broker.Message{
+		Header: map[string]string{"foo": "bar"},
+		Body:   []byte("hello"),
+	}
+	if err := b.Publish("orders.created", msg); err != nil {
+		t.Fatalf("Unexpected publish error %v", err)
+	}
+
+	select {
+	case e := <-received:
+		if e.Topic() != "orders.created" {
+			t.Fatalf("Expected topic orders.created got %s", e.Topic())
+		}
+		if string(e.Message().Body) != "hello" || e.Message().Header["foo"] != "bar" {
+			t.Fatalf("Unexpected message %+v", e.Message())
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for message")
+	}
+}
+
+func TestQueue(t *testing.T) {
+	addr := newServer(t)
+	pub := newBroker(t, addr)
+
+	received := make(chan string, 20)
+
+	for _, name := range []string{"a", "b"} {
+		name := name
+		b := newBroker(t, addr)
+		_, err := b.Subscribe("jobs", func(e broker.Event) error {
+			received <- name
+			return nil
+		}, broker.Queue("workers"))
+		if err != nil {
+			t.Fatalf("Unexpected subscribe error %v", err)
+		}
+	}
+
+	count := 10
+	for i := 0; i < count; i++ {
+		if err := pub.Publish("jobs", &broker.Message{Body: []byte("job")}); err != nil {
+			t.Fatalf("Unexpected publish error %v", err)
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		select {
+		case <-received:
+		case <-time.After(time.Second * 5):
+			t.Fatalf("Timed out waiting for message %d", i)
+		}
+	}
+
+	select {
+	case name := <-received:
+		t.Fatalf("Message delivered more than once to queue, extra on %s", name)
+	case <-time.After(time.Millisecond * 200):
+	}
+}
+
+func TestManualAck(t *testing.T) {
+	b := newBroker(t, newServer(t))
+
+	acked := make(chan error, 1)
+
+	_, err := b.Subscribe("acks", func(e broker.Event) error {
+		acked <- e.Ack()
+		return nil
+	}, broker.DisableAutoAck())
+	if err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+
+	if err := b.Publish("acks", &broker.Message{Body: []byte("ack me")}); err != nil {
+		t.Fatalf("Unexpected publish error %v", err)
+	}
+
+	select {
+	case err := <-acked:
+		if err != nil {
+			t.Fatalf("Unexpected ack error %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for message")
+	}
+}
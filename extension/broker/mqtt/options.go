@@ -0,0 +1,24 @@
+package mqtt
+
+import (
+	"c-z.dev/go-micro/broker"
+
+	"github.com/eclipse/paho.mqtt.golang"
+)
+
+type (
+	clientOptionsKey struct{}
+	qosKey           struct{}
+)
+
+// ClientOptions accepts mqtt.ClientOptions. Servers, TLS config, acking
+// and the persistent session are still controlled by the broker options.
+func ClientOptions(opts *mqtt.ClientOptions) broker.Option {
+	return setBrokerOption(clientOptionsKey{}, opts)
+}
+
+// QoS sets the quality of service used to publish and subscribe.
+// Defaults to 1, at least once delivery.
+func QoS(qos byte) broker.Option {
+	return setBrokerOption(qosKey{}, qos)
+}
@@ -0,0 +1,28 @@
+package flags
+
+import (
+	"context"
+)
+
+type flagsKey struct{}
+
+// FromContext returns the flags evaluated for the request
+func FromContext(ctx context.Context) (map[string]interface{}, bool) {
+	v, ok := ctx.Value(flagsKey{}).(map[string]interface{})
+	return v, ok
+}
+
+// NewContext sets the evaluated flags in the context
+func NewContext(ctx context.Context, v map[string]interface{}) context.Context {
+	return context.WithValue(ctx, flagsKey{}, v)
+}
+
+// Enabled returns whether the boolean flag evaluated for the request is set
+func Enabled(ctx context.Context, name string) bool {
+	v, ok := FromContext(ctx)
+	if !ok {
+		return false
+	}
+	b, _ := v[name].(bool)
+	return b
+}
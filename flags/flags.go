@@ -0,0 +1,292 @@
+// Package flags evaluates feature flags stored in config
+package flags
+
+import (
+	"context"
+	"hash/fnv"
+	"path"
+	"strings"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/config"
+	"c-z.dev/go-micro/metadata"
+	"c-z.dev/go-micro/server"
+)
+
+// Flags evaluates feature flags against the request context. Flags are
+// read from config so they change with it e.g.
+//
+//	{
+//		"flags": {
+//			"checkout": {
+//				"default": false,
+//				"rules": [
+//					{"match": {"account.type": ["user"], "metadata.X-Region": ["eu-*"]}, "value": true},
+//					{"rollout": 20, "value": true}
+//				]
+//			},
+//			"theme": {
+//				"default": "light",
+//				"rules": [
+//					{"variants": [{"value": "dark", "weight": 50}, {"value": "blue", "weight": 50}]}
+//				]
+//			}
+//		}
+//	}
+type Flags interface {
+	// Bool evaluates a boolean flag
+	Bool(ctx context.Context, name string, def bool) bool
+	// String evaluates a multivariate flag
+	String(ctx context.Context, name string, def string) string
+	// Value evaluates a flag, returning def if it's not set
+	Value(ctx context.Context, name string, def interface{}) interface{}
+	// Evaluate all the flags
+	Evaluate(ctx context.Context) map[string]interface{}
+}
+
+// Flag is a feature flag
+type Flag struct {
+	// Disabled flags always evaluate to the default
+	Disabled bool `json:"disabled"`
+	// Default value when no rule matches
+	Default interface{} `json:"default"`
+	// Rules evaluated in order, the first to match sets the value
+	Rules []*Rule `json:"rules"`
+}
+
+// Rule targets a value at requests
+type Rule struct {
+	// Match attributes to patterns, every attribute must match
+	// one of its patterns. Attributes are account.id, account.type,
+	// account.issuer, account.scope, metadata.<key>, service.name
+	// and service.version. Patterns may use * wildcards.
+	Match map[string][]string `json:"match"`
+	// Rollout is the percentage of matching requests the rule applies to
+	Rollout *float64 `json:"rollout"`
+	// Stick is the attribute which keeps a request in the same
+	// rollout bucket, it defaults to account.id
+	Stick string `json:"stick"`
+	// Value of the flag
+	Value interface{} `json:"value"`
+	// Variants split requests between values by weight
+	Variants []*Variant `json:"variants"`
+}
+
+// Variant is a weighted value
+type Variant struct {
+	Value  interface{} `json:"value"`
+	Weight float64     `json:"weight"`
+}
+
+var (
+	// DefaultPath is the path of the flags in the config
+	DefaultPath = []string{"flags"}
+)
+
+type flags struct {
+	opts Options
+}
+
+// NewFlags returns flags read from the config, which defaults to config.DefaultConfig
+func NewFlags(opts ...Option) Flags {
+	options := Options{
+		Config: config.DefaultConfig,
+		Path:   DefaultPath,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &flags{opts: options}
+}
+
+func (f *flags) flag(name string) (*Flag, bool) {
+	var flag *Flag
+	p := append(append([]string{}, f.opts.Path...), name)
+	if err := f.opts.Config.Get(p...).Scan(&flag); err != nil || flag == nil {
+		return nil, false
+	}
+	return flag, true
+}
+
+func (f *flags) Bool(ctx context.Context, name string, def bool) bool {
+	v, ok := f.Value(ctx, name, def).(bool)
+	if !ok {
+		return def
+	}
+	return v
+}
+
+func (f *flags) String(ctx context.Context, name string, def string) string {
+	v, ok := f.Value(ctx, name, def).(string)
+	if !ok {
+		return def
+	}
+	return v
+}
+
+func (f *flags) Value(ctx context.Context, name string, def interface{}) interface{} {
+	flag, ok := f.flag(name)
+	if !ok {
+		return def
+	}
+	if v := f.evaluate(ctx, name, flag); v != nil {
+		return v
+	}
+	return def
+}
+
+func (f *flags) Evaluate(ctx context.Context) map[string]interface{} {
+	var all map[string]*Flag
+	if err := f.opts.Config.Get(f.opts.Path...).Scan(&all); err != nil {
+		return map[string]interface{}{}
+	}
+
+	values := make(map[string]interface{}, len(all))
+	for name, flag := range all {
+		if flag == nil {
+			continue
+		}
+		if v := f.evaluate(ctx, name, flag); v != nil {
+			values[name] = v
+		}
+	}
+	return values
+}
+
+// evaluate the rules of the flag
+func (f *flags) evaluate(ctx context.Context, name string, flag *Flag) interface{} {
+	if flag.Disabled {
+		return flag.Default
+	}
+
+	for _, rule := range flag.Rules {
+		if rule == nil || !f.match(ctx, rule) {
+			continue
+		}
+
+		if rule.Rollout == nil && len(rule.Variants) == 0 {
+			return rule.Value
+		}
+
+		// rollouts need a stable bucket for the request
+		stick := rule.Stick
+		if len(stick) == 0 {
+			stick = "account.id"
+		}
+		key, ok := f.attribute(ctx, stick)
+		if !ok || len(key) == 0 {
+			continue
+		}
+		b := bucket(name, key)
+
+		if rule.Rollout != nil && b >= *rule.Rollout {
+			continue
+		}
+
+		if len(rule.Variants) == 0 {
+			return rule.Value
+		}
+
+		// variants split the bucket by weight
+		var total float64
+		for _, v := range rule.Variants {
+			total += v.Weight
+		}
+		if total <= 0 {
+			continue
+		}
+		vb := bucket(name+":variant", key) * total / 100
+		var sum float64
+		for _, v := range rule.Variants {
+			sum += v.Weight
+			if vb < sum {
+				return v.Value
+			}
+		}
+	}
+
+	return flag.Default
+}
+
+// match every attribute of the rule
+func (f *flags) match(ctx context.Context, rule *Rule) bool {
+	for attr, patterns := range rule.Match {
+		v, ok := f.attribute(ctx, attr)
+		if !ok {
+			return false
+		}
+
+		var matched bool
+		for _, p := range patterns {
+			if p == v {
+				matched = true
+				break
+			}
+			if ok, _ := path.Match(p, v); ok {
+				matched = true
+				break
+			}
+		}
+
+		// any scope of the account can match
+		if !matched && attr == "account.scope" {
+			acc, _ := auth.AccountFromContext(ctx)
+			for _, s := range acc.Scopes {
+				for _, p := range patterns {
+					if ok, _ := path.Match(p, s); ok {
+						matched = true
+					}
+				}
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// attribute returns the value of an attribute of the request
+func (f *flags) attribute(ctx context.Context, attr string) (string, bool) {
+	switch {
+	case strings.HasPrefix(attr, "account."):
+		acc, ok := auth.AccountFromContext(ctx)
+		if !ok || acc == nil {
+			return "", false
+		}
+		switch strings.TrimPrefix(attr, "account.") {
+		case "id":
+			return acc.ID, true
+		case "type":
+			return acc.Type, true
+		case "issuer":
+			return acc.Issuer, true
+		case "scope":
+			return strings.Join(acc.Scopes, " "), true
+		}
+		v, ok := acc.Metadata[strings.TrimPrefix(attr, "account.")]
+		return v, ok
+	case strings.HasPrefix(attr, "metadata."):
+		return metadata.Get(ctx, strings.TrimPrefix(attr, "metadata."))
+	case attr == "service.name", attr == "service.version":
+		name, version := f.opts.Name, f.opts.Version
+		if s, ok := server.FromContext(ctx); ok && len(name) == 0 {
+			name, version = s.Options().Name, s.Options().Version
+		}
+		if attr == "service.name" {
+			return name, len(name) > 0
+		}
+		return version, len(version) > 0
+	}
+	return "", false
+}
+
+// bucket returns a stable percentage in [0, 100) for the key
+func bucket(name, key string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return float64(h.Sum32()%10000) / 100
+}
@@ -0,0 +1,102 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/config"
+	"c-z.dev/go-micro/config/source/memory"
+	"c-z.dev/go-micro/metadata"
+)
+
+func TestFlags(t *testing.T) {
+	data := []byte(`{
+		"flags": {
+			"checkout": {
+				"default": false,
+				"rules": [
+					{"match": {"account.type": ["admin"]}, "value": true},
+					{"match": {"metadata.X-Region": ["eu-*"]}, "rollout": 50, "value": true}
+				]
+			},
+			"theme": {
+				"default": "light",
+				"rules": [
+					{"variants": [{"value": "dark", "weight": 50}, {"value": "blue", "weight": 50}]}
+				]
+			},
+			"legacy": {"disabled": true, "default": false, "rules": [{"value": true}]}
+		}
+	}`)
+
+	c, err := config.NewConfig(config.WithSource(memory.NewSource(memory.WithJSON(data))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewFlags(Config(c))
+
+	ctx := context.Background()
+
+	// nothing matches
+	if f.Bool(ctx, "checkout", true) {
+		t.Fatal("Expected checkout to be off")
+	}
+	// unknown flags return the default
+	if !f.Bool(ctx, "unknown", true) {
+		t.Fatal("Expected default for unknown flag")
+	}
+	if f.Bool(ctx, "legacy", false) {
+		t.Fatal("Expected disabled flag to be off")
+	}
+
+	admin := auth.ContextWithAccount(ctx, &auth.Account{ID: "1", Type: "admin"})
+	if !f.Bool(admin, "checkout", false) {
+		t.Fatal("Expected checkout to be on for admin")
+	}
+
+	// rollouts are sticky and roughly the percentage
+	var on int
+	for i := 0; i < 1000; i++ {
+		ctx := auth.ContextWithAccount(ctx, &auth.Account{ID: fmt.Sprintf("user-%d", i), Type: "user"})
+		ctx = metadata.NewContext(ctx, metadata.Metadata{"X-Region": "eu-west-1"})
+
+		v := f.Bool(ctx, "checkout", false)
+		for j := 0; j < 3; j++ {
+			if f.Bool(ctx, "checkout", false) != v {
+				t.Fatal("Expected rollout to be sticky")
+			}
+		}
+		if v {
+			on++
+		}
+	}
+	if on < 400 || on > 600 {
+		t.Fatalf("Expected about 500 accounts in the rollout but got %d", on)
+	}
+
+	// variants are split by weight
+	seen := map[string]int{}
+	for i := 0; i < 100; i++ {
+		ctx := auth.ContextWithAccount(ctx, &auth.Account{ID: fmt.Sprintf("user-%d", i)})
+		seen[f.String(ctx, "theme", "")]++
+	}
+	if seen["dark"] == 0 || seen["blue"] == 0 || seen["light"] != 0 {
+		t.Fatalf("Expected dark and blue variants but got %v", seen)
+	}
+
+	// without an account there's nothing to stick to
+	if v := f.String(ctx, "theme", ""); v != "light" {
+		t.Fatalf("Expected light but got %s", v)
+	}
+
+	vals := f.Evaluate(admin)
+	ctx = NewContext(ctx, vals)
+	if !Enabled(ctx, "checkout") {
+		t.Fatal("Expected checkout to be enabled in context")
+	}
+	if Enabled(ctx, "legacy") {
+		t.Fatal("Expected legacy to be disabled in context")
+	}
+}
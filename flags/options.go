@@ -0,0 +1,39 @@
+package flags
+
+import (
+	"c-z.dev/go-micro/config"
+)
+
+type Options struct {
+	// Config the flags are read from
+	Config config.Config
+	// Path of the flags in the config
+	Path []string
+	// Service name and version targeted by service.* rules
+	Name    string
+	Version string
+}
+
+type Option func(o *Options)
+
+// Config sets the config the flags are read from
+func Config(c config.Config) Option {
+	return func(o *Options) {
+		o.Config = c
+	}
+}
+
+// Path sets the path of the flags in the config
+func Path(path ...string) Option {
+	return func(o *Options) {
+		o.Path = path
+	}
+}
+
+// Service sets the name and version matched by service.name and service.version
+func Service(name, version string) Option {
+	return func(o *Options) {
+		o.Name = name
+		o.Version = version
+	}
+}
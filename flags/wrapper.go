@@ -0,0 +1,17 @@
+package flags
+
+import (
+	"context"
+
+	"c-z.dev/go-micro/server"
+)
+
+// NewHandlerWrapper evaluates the flags for each request and puts
+// them in the context, see FromContext and Enabled
+func NewHandlerWrapper(f Flags) server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			return h(NewContext(ctx, f.Evaluate(ctx)), req, rsp)
+		}
+	}
+}
@@ -7,6 +7,10 @@ import (
 
 type serverKey struct{}
 
+// subscriberTopicKey holds the topic or pattern of the
+// broker subscription a message was received on
+type subscriberTopicKey struct{}
+
 func wait(ctx context.Context) *sync.WaitGroup {
 	if ctx == nil {
 		return nil
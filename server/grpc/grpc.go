@@ -24,10 +24,12 @@ import (
 	meta "c-z.dev/go-micro/metadata"
 	"c-z.dev/go-micro/registry"
 	"c-z.dev/go-micro/server"
+	"c-z.dev/go-micro/transport"
 	"c-z.dev/go-micro/util/addr"
 	"c-z.dev/go-micro/util/backoff"
 	mgrpc "c-z.dev/go-micro/util/grpc"
 	mnet "c-z.dev/go-micro/util/net"
+	"c-z.dev/go-micro/util/pki"
 
 	"golang.org/x/net/netutil"
 
@@ -249,12 +251,24 @@ func (g *grpcServer) handler(srv interface{}, stream grpc.ServerStream) error {
 	delete(md, "x-content-type")
 	delete(md, "timeout")
 
+	// the peer identity is only set from a verified client certificate
+	for k := range md {
+		if strings.EqualFold(k, transport.PeerIdentityHeader) {
+			delete(md, k)
+		}
+	}
+
 	// create new context
 	ctx := meta.NewContext(stream.Context(), md)
 
 	// get peer from context
 	if p, ok := peer.FromContext(stream.Context()); ok {
 		md["Remote"] = p.Addr.String()
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if id, ok := pki.PeerIdentity(&info.State); ok {
+				md[transport.PeerIdentityHeader] = id
+			}
+		}
 		ctx = peer.NewContext(ctx, p)
 	}
 
@@ -253,7 +253,7 @@ func (g *grpcServer) createSubHandler(sb *subscriber, opts server.Options) broke
 					defer g.wg.Done()
 				}
 				err := fn(ctx, &rpcMessage{
-					topic:       sb.topic,
+					topic:       p.Topic(),
 					contentType: ct,
 					payload:     req.Interface(),
 					header:      msg.Header,
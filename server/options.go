@@ -40,6 +40,9 @@ type Options struct {
 	// The router for requests
 	Router Router
 
+	// Serve calls made over the broker
+	BrokerCalls bool
+
 	// TLSConfig specifies tls.Config for secure serving
 	TLSConfig *tls.Config
 
@@ -148,6 +151,15 @@ func Codec(contentType string, c codec.NewCodec) Option {
 	}
 }
 
+// BrokerCalls serves calls made over the broker in addition to the
+// transport. Calls are received on a topic shared by the nodes of
+// the service and the response is published to the caller's reply topic.
+func BrokerCalls() Option {
+	return func(o *Options) {
+		o.BrokerCalls = true
+	}
+}
+
 // Context specifies a context for the service.
 // Can be used to signal shutdown of the service
 // Can be used for extra option values.
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"c-z.dev/go-micro/broker"
+	"c-z.dev/go-micro/transport"
+)
+
+// brokerSocket is a transport socket for a call received over the
+// broker. It yields the request once and publishes the response to
+// the reply topic of the caller.
+type brokerSocket struct {
+	broker  broker.Broker
+	local   string
+	replyTo string
+	id      string
+
+	recv chan *transport.Message
+	once sync.Once
+	exit chan bool
+}
+
+// callTopic is the broker topic a service receives calls on,
+// it must match the topic the client publishes calls to
+func callTopic(service string) string {
+	return "go.micro.call." + service
+}
+
+func newBrokerSocket(b broker.Broker, local string, msg *broker.Message) *brokerSocket {
+	// anyone able to publish can set the peer identity
+	hdr := make(map[string]string, len(msg.Header))
+	for k, v := range msg.Header {
+		if strings.EqualFold(k, transport.PeerIdentityHeader) {
+			continue
+		}
+		hdr[k] = v
+	}
+
+	recv := make(chan *transport.Message, 1)
+	recv <- &transport.Message{
+		Header: hdr,
+		Body:   msg.Body,
+	}
+
+	return &brokerSocket{
+		broker:  b,
+		local:   local,
+		replyTo: msg.Header["Micro-Reply-To"],
+		id:      msg.Header["Micro-Correlation-Id"],
+		recv:    recv,
+		exit:    make(chan bool),
+	}
+}
+
+func (b *brokerSocket) Recv(m *transport.Message) error {
+	select {
+	case msg := <-b.recv:
+		*m = *msg
+		return nil
+	case <-b.exit:
+		return io.EOF
+	}
+}
+
+func (b *brokerSocket) Send(m *transport.Message) error {
+	hdr := make(map[string]string, len(m.Header)+1)
+	for k, v := range m.Header {
+		hdr[k] = v
+	}
+	hdr["Micro-Correlation-Id"] = b.id
+
+	// a call has a single response
+	defer b.Close()
+
+	return b.broker.Publish(b.replyTo, &broker.Message{
+		Header: hdr,
+		Body:   m.Body,
+	})
+}
+
+func (b *brokerSocket) Close() error {
+	b.once.Do(func() {
+		close(b.exit)
+	})
+	return nil
+}
+
+func (b *brokerSocket) Local() string {
+	return b.local
+}
+
+func (b *brokerSocket) Remote() string {
+	return b.replyTo
+}
+
+// handleCall serves a call received over the broker
+func (s *rpcServer) handleCall(e broker.Event) error {
+	msg := e.Message()
+	if msg == nil || len(msg.Header["Micro-Reply-To"]) == 0 {
+		return errors.New("call has no reply topic")
+	}
+
+	s.RLock()
+	config := s.opts
+	s.RUnlock()
+
+	// serve the call like a connection, the response is
+	// published when the handler returns
+	go s.ServeConn(newBrokerSocket(config.Broker, e.Topic(), msg))
+
+	return nil
+}
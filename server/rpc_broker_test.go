@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bmemory "c-z.dev/go-micro/broker/memory"
+	"c-z.dev/go-micro/client"
+	"c-z.dev/go-micro/errors"
+	"c-z.dev/go-micro/metadata"
+	"c-z.dev/go-micro/registry"
+	rmemory "c-z.dev/go-micro/registry/memory"
+	"c-z.dev/go-micro/transport"
+	tmemory "c-z.dev/go-micro/transport/memory"
+)
+
+type TestCallRequest struct {
+	Name  string `json:"name"`
+	Sleep int64  `json:"sleep"`
+}
+
+type TestCallResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+type TestCallHandler struct{}
+
+func (h *TestCallHandler) Hello(ctx context.Context, req *TestCallRequest, rsp *TestCallResponse) error {
+	if len(req.Name) == 0 {
+		return errors.BadRequest("test.call", "name is required")
+	}
+	time.Sleep(time.Duration(req.Sleep))
+	rsp.Greeting = "hello " + req.Name
+	return nil
+}
+
+func (h *TestCallHandler) Identity(ctx context.Context, req *TestCallRequest, rsp *TestCallResponse) error {
+	rsp.Greeting, _ = metadata.Get(ctx, transport.PeerIdentityHeader)
+	return nil
+}
+
+func TestBrokerCall(t *testing.T) {
+	b := bmemory.NewBroker()
+	r := rmemory.NewRegistry()
+
+	srv := NewServer(
+		Name("test.call"),
+		Broker(b),
+		Registry(r),
+		Transport(tmemory.NewTransport()),
+		BrokerCalls(),
+	)
+
+	if err := srv.Handle(srv.NewHandler(&TestCallHandler{})); err != nil {
+		t.Fatalf("Unexpected handle error %v", err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Unexpected start error %v", err)
+	}
+	defer srv.Stop()
+
+	c := client.NewClient(
+		client.Broker(b),
+		client.Registry(r),
+		client.ContentType("application/json"),
+	)
+
+	call := func(req *TestCallRequest, opts ...client.CallOption) (*TestCallResponse, error) {
+		rsp := new(TestCallResponse)
+		opts = append(opts, client.WithBrokerCall())
+		err := c.Call(context.TODO(), c.NewRequest("test.call", "TestCallHandler.Hello", req), rsp, opts...)
+		return rsp, err
+	}
+
+	rsp, err := call(&TestCallRequest{Name: "john"})
+	if err != nil {
+		t.Fatalf("Unexpected call error %v", err)
+	}
+	if rsp.Greeting != "hello john" {
+		t.Fatalf("Expected hello john got %s", rsp.Greeting)
+	}
+
+	_, err = call(&TestCallRequest{})
+	if verr := errors.FromError(err); verr.Code != 400 || verr.Detail != "name is required" {
+		t.Fatalf("Expected bad request error got %v", err)
+	}
+
+	_, err = call(&TestCallRequest{Name: "john", Sleep: int64(time.Millisecond * 500)}, client.WithRequestTimeout(time.Millisecond*50))
+	if verr := errors.FromError(err); verr.Code != 408 {
+		t.Fatalf("Expected timeout error got %v", err)
+	}
+
+	// broker calls go through the call wrappers and are retried
+	var calls int
+	wrapper := func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			calls++
+			if calls == 1 {
+				return errors.InternalServerError("test.call", "try again")
+			}
+			return cf(ctx, node, req, rsp, opts)
+		}
+	}
+	rsp, err = call(&TestCallRequest{Name: "jane"}, client.WithCallWrapper(wrapper), client.WithRetries(1))
+	if err != nil {
+		t.Fatalf("Unexpected call error %v", err)
+	}
+	if calls != 2 || rsp.Greeting != "hello jane" {
+		t.Fatalf("Expected a retried call got %d calls %s", calls, rsp.Greeting)
+	}
+
+	// the peer identity sent by the publisher is never trusted
+	ctx := metadata.NewContext(context.TODO(), metadata.Metadata{
+		transport.PeerIdentityHeader: "spiffe://example.org/admin",
+	})
+	rsp = new(TestCallResponse)
+	req := c.NewRequest("test.call", "TestCallHandler.Identity", &TestCallRequest{Name: "john"})
+	if err := c.Call(ctx, req, rsp, client.WithBrokerCall()); err != nil {
+		t.Fatalf("Unexpected call error %v", err)
+	}
+	if len(rsp.Greeting) > 0 {
+		t.Fatalf("Expected no peer identity got %s", rsp.Greeting)
+	}
+}
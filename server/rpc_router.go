@@ -18,6 +18,7 @@ import (
 	"unicode"
 	"unicode/utf8"
 
+	"c-z.dev/go-micro/broker"
 	"c-z.dev/go-micro/codec"
 	merrors "c-z.dev/go-micro/errors"
 )
@@ -515,11 +516,21 @@ func (router *router) ProcessMessage(ctx context.Context, msg Message) (err erro
 	}()
 
 	router.su.RLock()
-	// get the subscribers by topic
-	subs, ok := router.subscribers[msg.Topic()]
+	var subs []*subscriber
+	// get the subscribers by topic, a broker subscription
+	// for a pattern only routes to that pattern's subscribers
+	if topic, ok := ctx.Value(subscriberTopicKey{}).(string); ok {
+		subs = router.subscribers[topic]
+	} else {
+		for pattern, s := range router.subscribers {
+			if broker.Match(pattern, msg.Topic()) {
+				subs = append(subs, s...)
+			}
+		}
+	}
 	// unlock since we only need to get the subs
 	router.su.RUnlock()
-	if !ok {
+	if len(subs) == 0 {
 		return nil
 	}
 
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"c-z.dev/go-micro/codec/json"
+)
+
+type TestEvent struct {
+	Name string `json:"name"`
+}
+
+func TestRouterPatternSubscriber(t *testing.T) {
+	r := newRpcRouter()
+
+	received := make(map[string][]string)
+
+	subscribe := func(topic string) {
+		fn := func(ctx context.Context, e *TestEvent) error {
+			received[topic] = append(received[topic], e.Name)
+			return nil
+		}
+		if err := r.Subscribe(r.NewSubscriber(topic, fn)); err != nil {
+			t.Fatalf("Unexpected error subscribing to %s: %v", topic, err)
+		}
+	}
+
+	subscribe("orders.*")
+	subscribe("orders.created")
+	subscribe("users.>")
+
+	process := func(ctx context.Context, topic string) {
+		msg := &rpcMessage{
+			topic:       topic,
+			contentType: "application/json",
+			codec:       json.NewCodec,
+			body:        []byte(`{"name":"` + topic + `"}`),
+		}
+		if err := r.ProcessMessage(ctx, msg); err != nil {
+			t.Fatalf("Unexpected error processing %s: %v", topic, err)
+		}
+	}
+
+	// without a subscription topic every matching pattern is routed
+	process(context.Background(), "orders.created")
+	process(context.Background(), "users.eu.created")
+	process(context.Background(), "accounts.created")
+
+	if len(received["orders.*"]) != 1 || len(received["orders.created"]) != 1 {
+		t.Fatalf("Expected orders.created routed to both subscribers got %v", received)
+	}
+	if len(received["users.>"]) != 1 || received["users.>"][0] != "users.eu.created" {
+		t.Fatalf("Expected users.eu.created routed to users.> got %v", received)
+	}
+
+	// a broker subscription only routes to its own subscribers
+	ctx := context.WithValue(context.Background(), subscriberTopicKey{}, "orders.*")
+	process(ctx, "orders.created")
+
+	if len(received["orders.*"]) != 2 || len(received["orders.created"]) != 1 {
+		t.Fatalf("Expected orders.created routed to orders.* only got %v", received)
+	}
+}
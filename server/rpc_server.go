@@ -39,6 +39,8 @@ type rpcServer struct {
 	registered bool
 	// subscribe to service name
 	subscriber broker.Subscriber
+	// subscribe to calls made over the broker
+	callSubscriber broker.Subscriber
 	// graceful exit
 	wg *sync.WaitGroup
 
@@ -64,6 +66,18 @@ func newRpcServer(opts ...Option) Server {
 // HandleEvent handles inbound messages to the service directly
 // TODO: handle requests from an event. We won't send a response.
 func (s *rpcServer) HandleEvent(e broker.Event) error {
+	return s.handleEvent("", e)
+}
+
+// newSubHandler returns a broker handler which only routes
+// messages to the subscribers of the given topic or pattern
+func (s *rpcServer) newSubHandler(topic string) broker.Handler {
+	return func(e broker.Event) error {
+		return s.handleEvent(topic, e)
+	}
+}
+
+func (s *rpcServer) handleEvent(subTopic string, e broker.Event) error {
 	// formatting horrible cruft
 	msg := e.Message()
 
@@ -95,13 +109,22 @@ func (s *rpcServer) HandleEvent(e broker.Event) error {
 
 	// create context
 	ctx := metadata.NewContext(context.Background(), hdr)
+	if len(subTopic) > 0 {
+		ctx = context.WithValue(ctx, subscriberTopicKey{}, subTopic)
+	}
+
+	// the concrete topic the message was published to
+	topic := msg.Header["Micro-Topic"]
+	if len(topic) == 0 {
+		topic = e.Topic()
+	}
 
 	// TODO: inspect message header
 	// Micro-Service means a request
 	// Micro-Topic means a message
 
 	rpcMsg := &rpcMessage{
-		topic:       msg.Header["Micro-Topic"],
+		topic:       topic,
 		contentType: ct,
 		payload:     &raw.Frame{Data: msg.Body},
 		codec:       cf,
@@ -276,6 +299,16 @@ func (s *rpcServer) ServeConn(sock transport.Socket) {
 		hdr["Local"] = sock.Local()
 		hdr["Remote"] = sock.Remote()
 
+		// the peer identity is only trusted from a socket which verified it
+		for k := range hdr {
+			if strings.EqualFold(k, transport.PeerIdentityHeader) {
+				delete(hdr, k)
+			}
+		}
+		if id, ok := peerIdentity(sock); ok {
+			hdr[transport.PeerIdentityHeader] = id
+		}
+
 		// create new context with the metadata
 		ctx := metadata.NewContext(context.Background(), hdr)
 
@@ -698,7 +731,7 @@ func (s *rpcServer) Register() error {
 			opts = append(opts, broker.DisableAutoAck())
 		}
 
-		sub, err := config.Broker.Subscribe(sb.Topic(), s.HandleEvent, opts...)
+		sub, err := config.Broker.Subscribe(sb.Topic(), s.newSubHandler(sb.Topic()), opts...)
 		if err != nil {
 			return err
 		}
@@ -707,6 +740,19 @@ func (s *rpcServer) Register() error {
 		}
 		s.subscribers[sb] = []broker.Subscriber{sub}
 	}
+
+	// serve calls made over the broker, shared between the nodes
+	if config.BrokerCalls {
+		sub, err := config.Broker.Subscribe(callTopic(config.Name), s.handleCall, broker.Queue(config.Name))
+		if err != nil {
+			return err
+		}
+		if logger.V(logger.InfoLevel, logger.DefaultLogger) {
+			log.Infof("Subscribing to calls on topic: %s", sub.Topic())
+		}
+		s.callSubscriber = sub
+	}
+
 	if cacheService {
 		s.rsvc = service
 	}
@@ -786,6 +832,12 @@ func (s *rpcServer) Deregister() error {
 		s.subscriber = nil
 	}
 
+	// stop serving calls over the broker
+	if s.callSubscriber != nil {
+		s.callSubscriber.Unsubscribe()
+		s.callSubscriber = nil
+	}
+
 	for sb, subs := range s.subscribers {
 		for _, sub := range subs {
 			if logger.V(logger.InfoLevel, logger.DefaultLogger) {
@@ -2,6 +2,8 @@ package server
 
 import (
 	"sync"
+
+	"c-z.dev/go-micro/transport"
 )
 
 // waitgroup for global management of connections
@@ -30,3 +32,12 @@ func (w *waitGroup) Wait() {
 	// only wait on local group
 	w.lg.Wait()
 }
+
+// peerIdentity returns the identity of the peer if the socket verified it
+func peerIdentity(sock transport.Socket) (string, bool) {
+	i, ok := sock.(transport.Identifier)
+	if !ok {
+		return "", false
+	}
+	return i.PeerIdentity()
+}
@@ -7,7 +7,9 @@ import (
 	"c-z.dev/go-micro/logger"
 	"c-z.dev/go-micro/transport"
 	pb "c-z.dev/go-micro/transport/grpc/proto"
+	"c-z.dev/go-micro/util/pki"
 
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
 )
 
@@ -27,6 +29,9 @@ func (m *microTransport) Stream(ts pb.Transport_StreamServer) (err error) {
 	p, ok := peer.FromContext(ts.Context())
 	if ok {
 		sock.remote = p.Addr.String()
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			sock.identity, _ = pki.PeerIdentity(&info.State)
+		}
 	}
 
 	defer func() {
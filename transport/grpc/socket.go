@@ -1,6 +1,8 @@
 package grpc
 
 import (
+	"strings"
+
 	"c-z.dev/go-micro/transport"
 	pb "c-z.dev/go-micro/transport/grpc/proto"
 
@@ -19,6 +21,8 @@ type grpcTransportSocket struct {
 	stream pb.Transport_StreamServer
 	local  string
 	remote string
+	// identity of the peer's client certificate
+	identity string
 }
 
 func (g *grpcTransportClient) Local() string {
@@ -67,6 +71,11 @@ func (g *grpcTransportSocket) Remote() string {
 	return g.remote
 }
 
+// PeerIdentity returns the identity of the peer's verified client certificate
+func (g *grpcTransportSocket) PeerIdentity() (string, bool) {
+	return g.identity, len(g.identity) > 0
+}
+
 func (g *grpcTransportSocket) Recv(m *transport.Message) error {
 	if m == nil {
 		return nil
@@ -79,6 +88,19 @@ func (g *grpcTransportSocket) Recv(m *transport.Message) error {
 
 	m.Header = msg.Header
 	m.Body = msg.Body
+
+	// set the peer identity
+	if m.Header == nil {
+		m.Header = make(map[string]string)
+	}
+	for k := range m.Header {
+		if strings.EqualFold(k, transport.PeerIdentityHeader) {
+			delete(m.Header, k)
+		}
+	}
+	if len(g.identity) > 0 {
+		m.Header[transport.PeerIdentityHeader] = g.identity
+	}
 	return nil
 }
 
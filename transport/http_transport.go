@@ -16,6 +16,7 @@ import (
 	maddr "c-z.dev/go-micro/util/addr"
 	"c-z.dev/go-micro/util/buf"
 	mnet "c-z.dev/go-micro/util/net"
+	"c-z.dev/go-micro/util/pki"
 	mls "c-z.dev/go-micro/util/tls"
 
 	"golang.org/x/net/http2"
@@ -237,6 +238,9 @@ func (h *httpTransportSocket) Recv(m *Message) error {
 			}
 		}
 
+		// set the peer identity
+		h.setIdentity(m)
+
 		// return early early
 		return nil
 	}
@@ -280,9 +284,25 @@ func (h *httpTransportSocket) Recv(m *Message) error {
 	// set path
 	m.Header[":path"] = h.r.URL.Path
 
+	// set the peer identity
+	h.setIdentity(m)
+
 	return nil
 }
 
+// PeerIdentity returns the identity of the peer's verified client certificate
+func (h *httpTransportSocket) PeerIdentity() (string, bool) {
+	return pki.PeerIdentity(h.r.TLS)
+}
+
+// setIdentity sets the identity of the peer's client certificate
+func (h *httpTransportSocket) setIdentity(m *Message) {
+	delete(m.Header, PeerIdentityHeader)
+	if id, ok := h.PeerIdentity(); ok {
+		m.Header[PeerIdentityHeader] = id
+	}
+}
+
 func (h *httpTransportSocket) Send(m *Message) error {
 	if h.r.ProtoMajor == 1 {
 		// make copy of header
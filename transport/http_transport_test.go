@@ -1,10 +1,17 @@
 package transport
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"io"
+	"math/big"
 	"net"
+	"net/url"
 	"testing"
 	"time"
+
+	"c-z.dev/go-micro/util/pki"
 )
 
 func expectedPort(t *testing.T, expected string, lsn Listener) {
@@ -244,3 +251,94 @@ func TestHTTPTransportTimeout(t *testing.T) {
 
 	<-done
 }
+
+// testCert issues a certificate for the SPIFFE ID signed by the CA
+func testCert(t *testing.T, caCert, caKey []byte, id string) tls.Certificate {
+	pub, priv, err := pki.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(id)
+	csr, err := pki.CSR(pki.KeyPair(pub, priv), pki.URIs(u), pki.IPAddresses(net.ParseIP("127.0.0.1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := pki.Sign(caCert, caKey, csr,
+		pki.SerialNumber(big.NewInt(time.Now().UnixNano())),
+		pki.NotBefore(time.Now().Add(-time.Minute)),
+		pki.NotAfter(time.Now().Add(time.Hour)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{mustDecode(t, cert)}, PrivateKey: priv}
+}
+
+func mustDecode(t *testing.T, b []byte) []byte {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		t.Fatal("invalid PEM")
+	}
+	return block.Bytes
+}
+
+func TestHTTPTransportPeerIdentity(t *testing.T) {
+	pub, priv, err := pki.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, caKey, err := pki.CA(
+		pki.KeyPair(pub, priv),
+		pki.SerialNumber(big.NewInt(1)),
+		pki.NotBefore(time.Now().Add(-time.Minute)),
+		pki.NotAfter(time.Now().Add(time.Hour)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caCert)
+
+	server := NewTransport(TLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{testCert(t, caCert, caKey, "spiffe://example.org/server")},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}))
+	client := NewTransport(TLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{testCert(t, caCert, caKey, "spiffe://example.org/client")},
+		RootCAs:      pool,
+	}))
+
+	l, err := server.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go l.Accept(func(sock Socket) {
+		defer sock.Close()
+		var m Message
+		if err := sock.Recv(&m); err != nil {
+			return
+		}
+		sock.Send(&Message{Header: map[string]string{"Identity": m.Header[PeerIdentityHeader]}})
+	})
+
+	c, err := client.Dial(l.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// the client can't claim another identity
+	if err := c.Send(&Message{Header: map[string]string{PeerIdentityHeader: "spiffe://example.org/admin"}}); err != nil {
+		t.Fatal(err)
+	}
+	var m Message
+	if err := c.Recv(&m); err != nil {
+		t.Fatal(err)
+	}
+	if id := m.Header["Identity"]; id != "spiffe://example.org/client" {
+		t.Fatalf("Expected spiffe://example.org/client got %s", id)
+	}
+}
@@ -5,6 +5,10 @@ import (
 	"time"
 )
 
+// PeerIdentityHeader is set by transports to the SPIFFE ID of the peer's
+// verified client certificate. Any value sent by the peer is removed.
+const PeerIdentityHeader = "Micro-Peer-Identity"
+
 // Transport is an interface which is used for communication between
 // services. It uses connection based socket send/recv semantics and
 // has various implementations; http, grpc, quic.
@@ -29,6 +33,12 @@ type Socket interface {
 	Remote() string
 }
 
+// Identifier is implemented by sockets which verify the client certificate of the peer
+type Identifier interface {
+	// PeerIdentity returns the SPIFFE ID of the peer's verified client certificate
+	PeerIdentity() (string, bool)
+}
+
 type Client interface {
 	Socket
 }
@@ -0,0 +1,25 @@
+package pki
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// Identity returns the SPIFFE ID of a certificate, the first URI SAN with the spiffe scheme
+func Identity(cert *x509.Certificate) (string, bool) {
+	for _, u := range cert.URIs {
+		if u.Scheme == "spiffe" && len(u.Host) > 0 {
+			return u.String(), true
+		}
+	}
+	return "", false
+}
+
+// PeerIdentity returns the SPIFFE ID of the peer's certificate. Only
+// certificates verified during the handshake are considered.
+func PeerIdentity(cs *tls.ConnectionState) (string, bool) {
+	if cs == nil || len(cs.VerifiedChains) == 0 || len(cs.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return Identity(cs.VerifiedChains[0][0])
+}
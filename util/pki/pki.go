@@ -29,8 +29,9 @@ func CA(opts ...CertOption) ([]byte, []byte, error) {
 		Subject:               options.Subject,
 		DNSNames:              options.DNSNames,
 		IPAddresses:           options.IPAddresses,
+		URIs:                  options.URIs,
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		NotBefore:             options.NotBefore,
 		NotAfter:              options.NotAfter,
 		SerialNumber:          options.SerialNumber,
@@ -70,6 +71,7 @@ func CSR(opts ...CertOption) ([]byte, error) {
 		SignatureAlgorithm: x509.PureEd25519,
 		DNSNames:           options.DNSNames,
 		IPAddresses:        options.IPAddresses,
+		URIs:               options.URIs,
 	}
 	out := &bytes.Buffer{}
 	csr, err := x509.CreateCertificateRequest(rand.Reader, csrTemplate, options.Priv)
@@ -122,20 +124,24 @@ func Sign(CACrt, CAKey, CSR []byte, opts ...CertOption) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("csr is invalid: %w", err)
 	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("csr signature is invalid: %w", err)
+	}
 	template := &x509.Certificate{
 		SignatureAlgorithm:    x509.PureEd25519,
 		Subject:               csr.Subject,
 		DNSNames:              csr.DNSNames,
 		IPAddresses:           csr.IPAddresses,
+		URIs:                  csr.URIs,
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		NotBefore:             options.NotBefore,
 		NotAfter:              options.NotAfter,
 		SerialNumber:          options.SerialNumber,
 		BasicConstraintsValid: true,
 	}
 
-	x509Cert, err := x509.CreateCertificate(rand.Reader, template, caCrt, caCrt.PublicKey, caKey)
+	x509Cert, err := x509.CreateCertificate(rand.Reader, template, caCrt, csr.PublicKey, caKey)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't sign certificate: %w", err)
 	}
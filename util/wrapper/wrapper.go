@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"c-z.dev/go-micro/auth"
+	"c-z.dev/go-micro/auth/audit"
+	"c-z.dev/go-micro/auth/mtls"
 	"c-z.dev/go-micro/client"
 	"c-z.dev/go-micro/debug/stats"
 	"c-z.dev/go-micro/debug/trace"
@@ -187,19 +189,43 @@ func AuthHandler(fn func() auth.Auth) server.HandlerWrapper {
 				return h(ctx, req, rsp)
 			}
 
+			// construct the resource
+			res := &auth.Resource{
+				Type:     "service",
+				Name:     req.Service(),
+				Endpoint: req.Endpoint(),
+			}
+
+			// The auditor records the decisions, the ones made by the rules are
+			// recorded when verifying
+			auditor, _ := audit.FromContext(a.Options().Context)
+			deny := func(account *auth.Account, err error) error {
+				if auditor != nil {
+					auditor.Record(denied(ctx, account, res, err))
+				}
+				return err
+			}
+
 			// Extract the token if present. Note: if noop is being used
 			// then the token can be blank without erroring
 			var account *auth.Account
 			if header, ok := metadata.Get(ctx, "Authorization"); ok {
 				// Ensure the correct scheme is being used
 				if !strings.HasPrefix(header, auth.BearerScheme) {
-					return errors.Unauthorized(req.Service(), "invalid authorization header. expected Bearer schema")
+					return deny(nil, errors.Unauthorized(req.Service(), "invalid authorization header. expected Bearer schema"))
 				}
 
 				// Strip the prefix and inspect the resulting token
 				account, _ = a.Inspect(strings.TrimPrefix(header, auth.BearerScheme))
 			}
 
+			// Fall back to the identity of the peer's client certificate which is
+			// issued by its trust domain rather than the namespace
+			var peer bool
+			if account == nil {
+				account, peer = mtls.AccountFromContext(ctx)
+			}
+
 			// Extract the namespace header
 			ns, ok := metadata.Get(ctx, "Micro-Namespace")
 			if !ok {
@@ -209,19 +235,16 @@ func AuthHandler(fn func() auth.Auth) server.HandlerWrapper {
 
 			// Check the issuer matches the services namespace. TODO: Stop allowing go.micro to access
 			// any namespace and instead check for the server issuer.
-			if account != nil && account.Issuer != ns && account.Issuer != "go.micro" {
-				return errors.Forbidden(req.Service(), "Account was not issued by %v", ns)
-			}
-
-			// construct the resource
-			res := &auth.Resource{
-				Type:     "service",
-				Name:     req.Service(),
-				Endpoint: req.Endpoint(),
+			if account != nil && !peer && account.Issuer != ns && account.Issuer != "go.micro" {
+				return deny(account, errors.Forbidden(req.Service(), "Account was not issued by %v", ns))
 			}
 
 			// Verify the caller has access to the resource
-			err := a.Verify(account, res, auth.VerifyContext(ctx))
+			vctx := ctx
+			if auditor != nil {
+				vctx = audit.NewContext(vctx, auditor)
+			}
+			err := a.Verify(account, res, auth.VerifyContext(vctx))
 			if err != nil && account != nil {
 				return errors.Forbidden(req.Service(), "Forbidden call made to %v:%v by %v", req.Service(), req.Endpoint(), account.ID)
 			} else if err != nil {
@@ -239,6 +262,22 @@ func AuthHandler(fn func() auth.Auth) server.HandlerWrapper {
 	}
 }
 
+// denied returns the audit event for a request rejected before it was verified
+func denied(ctx context.Context, account *auth.Account, res *auth.Resource, err error) *audit.Event {
+	e := &audit.Event{
+		Resource: res,
+		Decision: audit.DecisionDenied,
+		Reason:   err.Error(),
+	}
+	if account != nil {
+		e.Account = account.ID
+		e.Issuer = account.Issuer
+		e.Scopes = account.Scopes
+	}
+	e.Caller, _ = metadata.Get(ctx, HeaderPrefix+"From-Service")
+	return e
+}
+
 type cacheWrapper struct {
 	cacheFn func() *client.Cache
 	client.Client